@@ -0,0 +1,41 @@
+package canvas
+
+import "image/color"
+
+type CellKind uint8
+
+const (
+	Fill CellKind = iota
+	Edge
+)
+
+// Cell is one character of the art. Zero Rune means the cell is not drawn yet,
+// zero alpha in FG or BG means the color is not set
+type Cell struct {
+	Rune rune
+	FG   color.NRGBA
+	BG   color.NRGBA
+	Kind CellKind
+}
+
+type Canvas struct {
+	Width  int
+	Height int
+	Cells  []Cell
+}
+
+func New(width, height int) *Canvas {
+	return &Canvas{
+		Width:  width,
+		Height: height,
+		Cells:  make([]Cell, width*height),
+	}
+}
+
+func (c *Canvas) At(x, y int) *Cell {
+	return &c.Cells[y*c.Width+x]
+}
+
+func (c *Canvas) Row(y int) []Cell {
+	return c.Cells[y*c.Width : (y+1)*c.Width]
+}
@@ -0,0 +1,62 @@
+package effects
+
+import (
+	"image"
+	"image/color"
+	"math"
+	"testing"
+)
+
+func TestGaussianKernelNormalized(t *testing.T) {
+	for _, sigma := range []float64{0.5, 1, 2.5, 3} {
+		kernel := generateGaussianKernel(sigma, DefaultKernelRadius)
+		if len(kernel)%2 != 1 {
+			t.Fatalf("sigma %v: kernel size %d is even", sigma, len(kernel))
+		}
+		sum := 0.0
+		for i, v := range kernel {
+			sum += v
+			if mirrored := kernel[len(kernel)-1-i]; v != mirrored {
+				t.Errorf("sigma %v: kernel[%d] = %v, mirrored %v", sigma, i, v, mirrored)
+			}
+		}
+		if math.Abs(sum-1) > 1e-9 {
+			t.Errorf("sigma %v: kernel sum = %v, want 1", sigma, sum)
+		}
+	}
+}
+
+func TestGaussianBlurShiftFree(t *testing.T) {
+	// the impulse isn't at the origin so offsets of the bounds are covered too
+	bounds := image.Rect(5, 7, 26, 28)
+	center := image.Pt(15, 17)
+	im := image.NewNRGBA(bounds)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			im.SetNRGBA(x, y, color.NRGBA{A: 255})
+		}
+	}
+	im.SetNRGBA(center.X, center.Y, color.NRGBA{R: 255, G: 255, B: 255, A: 255})
+
+	blurs := map[string]func(image.Image, float64, float64, Border) *image.NRGBA{
+		"GaussianBlur":   GaussianBlur,
+		"GaussianBlur2D": GaussianBlur2D,
+	}
+	for name, blur := range blurs {
+		out := blur(im, 1.5, DefaultKernelRadius, Border{})
+		peak := out.NRGBAAt(center.X, center.Y).R
+		for dy := -6; dy <= 6; dy++ {
+			for dx := -6; dx <= 6; dx++ {
+				v := out.NRGBAAt(center.X+dx, center.Y+dy).R
+				if v > peak {
+					t.Errorf("%s: (%d, %d) = %d is above the center %d", name, dx, dy, v, peak)
+				}
+				for _, m := range []image.Point{{-dx, dy}, {dx, -dy}, {-dx, -dy}} {
+					if mv := out.NRGBAAt(center.X+m.X, center.Y+m.Y).R; mv != v {
+						t.Errorf("%s: (%d, %d) = %d, mirrored (%d, %d) = %d", name, dx, dy, v, m.X, m.Y, mv)
+					}
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package effects
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+)
+
+type BorderMode int
+
+const (
+	// BorderClamp repeats the edge pixel: aaa|abcd|ddd
+	BorderClamp BorderMode = iota
+	// BorderReflect mirrors the image: cba|abcd|dcb
+	BorderReflect
+	// BorderWrap takes pixels from the opposite side: bcd|abcd|abc
+	BorderWrap
+	// BorderConstant uses Border.Color outside of the image
+	BorderConstant
+)
+
+// Border tells convolutions what is outside of the image
+type Border struct {
+	Mode  BorderMode
+	Color color.NRGBA
+}
+
+func ParseBorderMode(s string) (BorderMode, error) {
+	switch s {
+	case "clamp", "replicate":
+		return BorderClamp, nil
+	case "reflect":
+		return BorderReflect, nil
+	case "wrap":
+		return BorderWrap, nil
+	case "constant":
+		return BorderConstant, nil
+	}
+	return BorderClamp, fmt.Errorf("unknown border mode %q", s)
+}
+
+// at returns color of the pixel, x and y can be outside of the image bounds
+func (b Border) at(im image.Image, x, y int) color.Color {
+	bounds := im.Bounds()
+	if b.Mode == BorderConstant && !(image.Point{X: x, Y: y}).In(bounds) {
+		return b.Color
+	}
+	return im.At(b.coord(x, bounds.Min.X, bounds.Max.X), b.coord(y, bounds.Min.Y, bounds.Max.Y))
+}
+
+// coord maps coord into [boundMin, boundMax)
+func (b Border) coord(coord, boundMin, boundMax int) int {
+	if coord >= boundMin && coord < boundMax {
+		return coord
+	}
+	size := boundMax - boundMin
+	switch b.Mode {
+	case BorderReflect:
+		period := 2 * size
+		m := ((coord-boundMin)%period + period) % period
+		if m >= size {
+			m = period - 1 - m
+		}
+		return boundMin + m
+	case BorderWrap:
+		return boundMin + ((coord-boundMin)%size+size)%size
+	}
+	return clampToBorders(coord, boundMin, boundMax-1)
+}
@@ -0,0 +1,96 @@
+package effects
+
+import (
+	"ascii/canvas"
+	"image"
+	"image/color"
+	"sync"
+)
+
+// brailleDots are the bits of the braille dots, indexed by row and column of
+// the 2x4 dot matrix
+var brailleDots = [4][2]rune{
+	{0x01, 0x08},
+	{0x02, 0x10},
+	{0x04, 0x20},
+	{0x40, 0x80},
+}
+
+// bayer4 is the 4x4 ordered dithering matrix
+var bayer4 = [4][4]float64{
+	{0, 8, 2, 10},
+	{12, 4, 14, 6},
+	{3, 11, 1, 9},
+	{15, 7, 13, 5},
+}
+
+// brailleFill draws every cell as a braille pattern of 2x4 dots. A dot is set
+// when its pixels have an edge, other dots are set by the difference of
+// gaussians or, with dither, by ordered dithering of the grayscale image
+func brailleFill(edges, dog, gray *image.NRGBA, art *canvas.Canvas, grid Grid, invert, dither bool) {
+	w := new(sync.WaitGroup)
+	for row := 0; row < grid.Rows; row++ {
+		w.Add(1)
+		go func(row int) {
+			defer w.Done()
+			for col := 0; col < grid.Columns; col++ {
+				letter := rune(0x2800)
+				for j := 0; j < 4; j++ {
+					for i := 0; i < 2; i++ {
+						block := grid.SubBlock(col, row, i, j, 2, 4)
+						if block.Empty() {
+							continue
+						}
+						on := hasEdge(edges, block)
+						if !on {
+							var level float64
+							if dither {
+								level = meanGray(gray, block)
+							} else {
+								level = meanGray(dog, block)
+							}
+							if invert {
+								level = 1 - level
+							}
+							threshold := 0.5
+							if dither {
+								threshold = (bayer4[(row*4+j)%4][(col*2+i)%4] + 0.5) / 16
+							}
+							on = level > threshold
+						}
+						if on {
+							letter |= brailleDots[j][i]
+						}
+					}
+				}
+				c := art.At(col, row)
+				c.Rune = letter
+				c.Kind = canvas.Fill
+			}
+		}(row)
+	}
+	w.Wait()
+}
+
+func hasEdge(edges *image.NRGBA, block image.Rectangle) bool {
+	black := color.NRGBA{A: 255}
+	for y := block.Min.Y; y < block.Max.Y; y++ {
+		for x := block.Min.X; x < block.Max.X; x++ {
+			if edges.NRGBAAt(x, y) != black {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// meanGray returns the mean of the red channel of a grayscale image from 0 to 1
+func meanGray(im *image.NRGBA, block image.Rectangle) float64 {
+	sum := 0
+	for y := block.Min.Y; y < block.Max.Y; y++ {
+		for x := block.Min.X; x < block.Max.X; x++ {
+			sum += int(im.NRGBAAt(x, y).R)
+		}
+	}
+	return float64(sum) / float64(block.Dx()*block.Dy()*255)
+}
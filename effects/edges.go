@@ -0,0 +1,95 @@
+package effects
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+)
+
+// EdgeSet is the set of letters for edge cells
+type EdgeSet int
+
+const (
+	// EdgeASCII draws edges with | _ / \
+	EdgeASCII EdgeSet = iota
+	// EdgeUnicode draws edges with box-drawing letters ─ │ ╱ ╲, strong
+	// horizontal and vertical edges are heavy ━ ┃
+	EdgeUnicode
+)
+
+func ParseEdgeSet(s string) (EdgeSet, error) {
+	switch s {
+	case "ascii":
+		return EdgeASCII, nil
+	case "unicode":
+		return EdgeUnicode, nil
+	}
+	return EdgeASCII, fmt.Errorf("unknown edge set %q", s)
+}
+
+// letter returns the letter of the set for the orientation.
+// There are no heavy diagonals among box-drawing letters
+func (s EdgeSet) letter(o EdgeOrientation, heavy bool) rune {
+	if s != EdgeUnicode {
+		return o.letter()
+	}
+	switch o {
+	case EdgeVertical:
+		if heavy {
+			return '┃'
+		}
+		return '│'
+	case EdgeHorizontal:
+		if heavy {
+			return '━'
+		}
+		return '─'
+	case EdgeRising:
+		return '╱'
+	case EdgeFalling:
+		return '╲'
+	}
+	return o.letter()
+}
+
+// Magnitudes are gradient magnitudes of the pixels in Bounds, row by row
+type Magnitudes struct {
+	Bounds image.Rectangle
+	Values []float64
+}
+
+func NewMagnitudes(bounds image.Rectangle) *Magnitudes {
+	return &Magnitudes{Bounds: bounds, Values: make([]float64, bounds.Dx()*bounds.Dy())}
+}
+
+func (m *Magnitudes) index(x, y int) int {
+	return (y-m.Bounds.Min.Y)*m.Bounds.Dx() + x - m.Bounds.Min.X
+}
+
+func (m *Magnitudes) At(x, y int) float64 {
+	return m.Values[m.index(x, y)]
+}
+
+func (m *Magnitudes) Set(x, y int, v float64) {
+	m.Values[m.index(x, y)] = v
+}
+
+// edgeMean returns the mean magnitude of the edge pixels of the block,
+// edges is the image of SobelOperatorAngleColored
+func (m *Magnitudes) edgeMean(edges image.Image, block image.Rectangle) float64 {
+	var sum float64
+	n := 0
+	for y := block.Min.Y; y < block.Max.Y; y++ {
+		for x := block.Min.X; x < block.Max.X; x++ {
+			if colorOrientation(edges.At(x, y).(color.NRGBA)) == EdgeNone {
+				continue
+			}
+			sum += m.At(x, y)
+			n++
+		}
+	}
+	if n == 0 {
+		return 0
+	}
+	return sum / float64(n)
+}
@@ -1,24 +1,23 @@
 package effects
 
 import (
+	"ascii/canvas"
+	"ascii/output"
 	"ascii/pixel"
+	"context"
 	"errors"
-	"fmt"
 	"image"
 	"image/color"
 	"log"
 	"math"
 	"os"
-	"strings"
 	"sync"
-
-	"github.com/disintegration/imaging"
 )
 
-func GaussianBlur2D(im image.Image, sigma float64) *image.NRGBA {
+func GaussianBlur2D(im image.Image, sigma, truncate float64, border Border) *image.NRGBA {
 	bounds := im.Bounds()
 	blurredImage := image.NewNRGBA(bounds)
-	kernel := generateGaussianKernel2D(sigma)
+	kernel := generateGaussianKernel2D(sigma, truncate)
 	radius := len(kernel) / 2
 	w := new(sync.WaitGroup)
 	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
@@ -29,16 +28,14 @@ func GaussianBlur2D(im image.Image, sigma float64) *image.NRGBA {
 				var newR, newG, newB float64
 				for ky := -radius; ky <= radius; ky++ {
 					for kx := -radius; kx <= radius; kx++ {
-						clampedX := clampToBorders(x+kx, bounds.Min.X, bounds.Max.X-1)
-						clampedY := clampToBorders(y+ky, bounds.Min.Y, bounds.Max.Y-1)
-						r, g, b, _ := im.At(clampedX, clampedY).RGBA()
+						r, g, b, _ := border.at(im, x+kx, y+ky).RGBA()
 						weight := kernel[ky+radius][kx+radius]
 						newR += weight * float64(r>>8)
 						newG += weight * float64(g>>8)
 						newB += weight * float64(b>>8)
 					}
 				}
-				color := color.NRGBA{R: clamp(int(newR)), G: clamp(int(newG)), B: clamp(int(newB)), A: 255}
+				color := color.NRGBA{R: clamp(int(math.Round(newR))), G: clamp(int(math.Round(newG))), B: clamp(int(math.Round(newB))), A: 255}
 				blurredImage.Set(x, y, color)
 			}
 		}(y)
@@ -47,113 +44,96 @@ func GaussianBlur2D(im image.Image, sigma float64) *image.NRGBA {
 	return blurredImage
 }
 
-func GaussianBlur(im image.Image, sigma float64) *image.NRGBA {
-	return gaussianBlurVertical(gaussianBlurHorizontal(im, sigma), sigma)
+func GaussianBlur(im image.Image, sigma, truncate float64, border Border) *image.NRGBA {
+	kernel := generateGaussianKernel(sigma, truncate)
+	return gaussianBlurVertical(gaussianBlurHorizontal(im, kernel, border), kernel, border)
 }
 
-func GenerateAsciiFiles(im image.Image, asciiTexture []string, addColors bool) error {
-	bounds := im.Bounds()
-	bordersImage := GaussianDifference(im, 0.5, 6, 120)
-	bordersImage = SobelOperatorAngleColored(bordersImage, 1200)
-	grayscaleImage := imaging.AdjustSaturation(im, -100)
-	art := AsciiBorders(bordersImage, 4)
-	w := new(sync.WaitGroup)
-	for y := bounds.Min.Y; y < bounds.Max.Y; y += 8 {
-		w.Add(1)
-		go func(y int) {
-			defer w.Done()
-			for x := bounds.Min.X; x < bounds.Max.X; x += 8 {
-				if art[y/8][x/8] != "" {
-					continue
-				}
-				blockWidth := min(8, bounds.Max.X-x)
-				pixelColor := grayscaleImage.At(x, y)
-				luminance := pixel.GetLuminanceGrayscale(pixelColor)
-				// 1-10 -> 0-9 because this is used as index
-				if luminance > 0 {
-					luminance--
-				}
-				letter := asciiTexture[luminance]
-				art[y/8][x/8] = letter
-				if x == bounds.Max.X-blockWidth {
-					art[y/8][x/8+1] = "\n"
-				}
-			}
-		}(y)
-	}
-	w.Wait()
-	if addColors {
-		art = AsciiAddColors(im, art, 8)
+func GenerateAsciiFiles(im image.Image, opts Options) error {
+	res, err := Render(context.Background(), im, opts)
+	if err != nil {
+		return err
 	}
 
-	var result string
-	for i := 0; i < len(art); i++ {
-		result += strings.Join(art[i], "")
+	f, err := os.Create(opts.Output)
+	if err != nil {
+		return errors.New("Couldn't write to html file: " + err.Error())
 	}
-
-	err := os.WriteFile("ascii-result.html", []byte(generateHTML(result)), 0666)
+	defer f.Close()
+	err = output.WriteHTML(f, res.Canvas)
 	if err != nil {
 		return errors.New("Couldn't write to html file: " + err.Error())
 	}
-	return nil
+	return f.Close()
 }
 
-func AsciiBorders(im image.Image, threshold int) [][]string {
-	bounds := im.Bounds()
+// AsciiBorders draws edge letters of the set in cells with enough edge pixels,
+// horizontal and vertical edges are heavy when the mean of magnitudes over the
+// edge pixels of the cell is above heavyThreshold, nil magnitudes are all
+// light. With junctions cells where edges meet get corners and crossings
+func AsciiBorders(im image.Image, magnitudes *Magnitudes, threshold int, grid Grid, edges EdgeSet, heavyThreshold float64, junctions bool) *canvas.Canvas {
 	wg := new(sync.WaitGroup)
-	art := make([][]string, bounds.Max.Y/8)
-	for i := 0; i < len(art); i++ {
-		art[i] = make([]string, bounds.Max.X/8+1)
-	}
+	art := grid.NewCanvas()
 
-	for y := bounds.Min.Y; y < bounds.Max.Y; y += 8 {
+	for row := 0; row < grid.Rows; row++ {
 		wg.Add(1)
-		go func(y int) {
+		go func(row int) {
 			defer wg.Done()
-			for x := bounds.Min.X; x < bounds.Max.X; x += 8 {
-
-				// we either have 8x8 or less block
-				blockHeight := min(8, bounds.Max.Y-y)
-				blockWidth := min(8, bounds.Max.X-x)
-
-				verticalSum := 0
-				horizontalSum := 0
-				diagonalFrontSum := 0
-				diagonalBackSum := 0
-				for by := 0; by < blockHeight; by++ {
-					for bx := 0; bx < blockWidth; bx++ {
-						clr := im.At(x+bx, y+by).(color.NRGBA)
-
-						switch clr {
-						case color.NRGBA{R: 0, G: 0, B: 255, A: 255}:
-							verticalSum++
-						case color.NRGBA{R: 0, G: 255, B: 0, A: 255}:
-							diagonalFrontSum++
-						case color.NRGBA{R: 255, G: 0, B: 0, A: 255}:
-							horizontalSum++
-						case color.NRGBA{R: 255, G: 255, B: 0, A: 255}:
-							diagonalBackSum++
+			for col := 0; col < grid.Columns; col++ {
+				block := grid.Block(col, row)
+
+				var counts [edgeOrientations]int
+				arms := edgeArms{block: block}
+				for y := block.Min.Y; y < block.Max.Y; y++ {
+					for x := block.Min.X; x < block.Max.X; x++ {
+						o := colorOrientation(im.At(x, y).(color.NRGBA))
+						if o == EdgeNone {
+							continue
 						}
+						counts[o]++
+						arms.add(o, x, y)
 					}
 				}
-				letter := determineEdgeLetter(horizontalSum, verticalSum, diagonalFrontSum, diagonalBackSum, threshold)
-				if letter == "" {
+				orientation := determineEdgeOrientation(counts, threshold)
+				if orientation == EdgeNone {
 					continue
 				}
-				art[y/8][x/8] = letter
-				if x == bounds.Max.X-blockWidth {
-					art[y/8][x/8+1] = "\n"
+				cell := art.At(col, row)
+				heavy := magnitudes != nil && magnitudes.edgeMean(im, block) > heavyThreshold
+				cell.Rune = edges.letter(orientation, heavy)
+				if junctions {
+					if junction := arms.junction(counts, threshold, edges); junction != 0 {
+						cell.Rune = junction
+					}
 				}
+				cell.Kind = canvas.Edge
 			}
-		}(y)
+		}(row)
 	}
 	wg.Wait()
 	return art
 }
 
-func GaussianDifference(im image.Image, sigma, k float64, threshold int) *image.NRGBA {
-	im = imaging.AdjustSaturation(im, -100)
-	// im = imaging.Grayscale(im)
+func Grayscale(im image.Image, model pixel.LuminanceModel) *image.NRGBA {
+	bounds := im.Bounds()
+	grayImage := image.NewNRGBA(bounds)
+	w := new(sync.WaitGroup)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		w.Add(1)
+		go func(y int) {
+			defer w.Done()
+			for x := bounds.Min.X; x < bounds.Max.X; x++ {
+				v := clamp(int(math.Round(model.Luminance(im.At(x, y)) * 255)))
+				grayImage.SetNRGBA(x, y, color.NRGBA{R: v, G: v, B: v, A: 255})
+			}
+		}(y)
+	}
+	w.Wait()
+	return grayImage
+}
+
+// GaussianDifference expects a grayscale image, see Grayscale
+func GaussianDifference(im image.Image, sigma, k, truncate float64, threshold int, border Border) *image.NRGBA {
 	bounds := im.Bounds()
 	w := new(sync.WaitGroup)
 	var blurred *image.NRGBA
@@ -161,11 +141,11 @@ func GaussianDifference(im image.Image, sigma, k float64, threshold int) *image.
 	w.Add(2)
 	go func() {
 		defer w.Done()
-		blurred = GaussianBlur(im, sigma)
+		blurred = GaussianBlur(im, sigma, truncate, border)
 	}()
 	go func() {
 		defer w.Done()
-		blurred2 = GaussianBlur(im, k*sigma)
+		blurred2 = GaussianBlur(im, k*sigma, truncate, border)
 	}()
 	w.Wait()
 	newImage := image.NewNRGBA(bounds)
@@ -201,7 +181,7 @@ func GaussianDifference(im image.Image, sigma, k float64, threshold int) *image.
 	return newImage
 }
 
-func SobelOperator(im image.Image, threshhold float64) *image.NRGBA {
+func SobelOperator(im image.Image, threshhold float64, border Border) *image.NRGBA {
 	bounds := im.Bounds()
 	newImage := image.NewNRGBA(bounds)
 	radius := len(sobelKernelHorizontal) / 2
@@ -212,9 +192,7 @@ func SobelOperator(im image.Image, threshhold float64) *image.NRGBA {
 			var sumY int
 			for ky := -radius; ky <= radius; ky++ {
 				for kx := -radius; kx <= radius; kx++ {
-					clampedX := clampToBorders(x+kx, bounds.Min.X, bounds.Max.X)
-					clampedY := clampToBorders(y+ky, bounds.Min.Y, bounds.Max.Y)
-					r, _, _, _ := im.At(clampedX, clampedY).RGBA()
+					r, _, _, _ := border.at(im, x+kx, y+ky).RGBA()
 					sumX += sobelKernelHorizontal[ky+radius][kx+radius] * int(r)
 					sumY += sobelKernelVertical[ky+radius][kx+radius] * int(r)
 
@@ -231,7 +209,8 @@ func SobelOperator(im image.Image, threshhold float64) *image.NRGBA {
 	return newImage
 }
 
-func SobelOperatorAngleColored(im image.Image, threshold float64) *image.NRGBA {
+// SobelOperatorAngleColored colors edge pixels by their orientation
+func SobelOperatorAngleColored(im image.Image, threshold float64, border Border) *image.NRGBA {
 	bounds := im.Bounds()
 	newImage := image.NewNRGBA(bounds)
 	radius := len(sobelKernelHorizontal) / 2
@@ -242,9 +221,7 @@ func SobelOperatorAngleColored(im image.Image, threshold float64) *image.NRGBA {
 			var sumY int
 			for ky := -radius; ky <= radius; ky++ {
 				for kx := -radius; kx <= radius; kx++ {
-					clampedX := clampToBorders(x+kx, bounds.Min.X, bounds.Max.X)
-					clampedY := clampToBorders(y+ky, bounds.Min.Y, bounds.Max.Y)
-					r, _, _, _ := im.At(clampedX, clampedY).RGBA()
+					r, _, _, _ := border.at(im, x+kx, y+ky).RGBA()
 					sumX += sobelKernelHorizontal[ky+radius][kx+radius] * int(r)
 					sumY += sobelKernelVertical[ky+radius][kx+radius] * int(r)
 
@@ -256,29 +233,41 @@ func SobelOperatorAngleColored(im image.Image, threshold float64) *image.NRGBA {
 				continue
 			}
 
-			angle := math.Atan2(float64(sumY), float64(sumX))
-			normalizedAngle := (angle + math.Pi) / (2 * math.Pi) // Normalize to 0-1
-			normalizedAngle = math.Mod(normalizedAngle*360, 180) // Normalize to 0-180 degrees
-			var slopeColor color.NRGBA
-			switch {
-			case normalizedAngle < 22.5 || normalizedAngle >= 157.5:
-				slopeColor = color.NRGBA{R: 0, G: 0, B: 255, A: 255} // Horizontal (_)
-			case normalizedAngle >= 112.5 && normalizedAngle < 157.5:
-				slopeColor = color.NRGBA{R: 0, G: 255, B: 0, A: 255} // Diagonal should be (/) but it's actually (\)
-			case normalizedAngle >= 67.5 && normalizedAngle < 112.5:
-				slopeColor = color.NRGBA{R: 255, G: 0, B: 0, A: 255} // Vertical (|)
-			case normalizedAngle >= 22.5 && normalizedAngle < 67.5:
-				slopeColor = color.NRGBA{R: 255, G: 255, B: 0, A: 255} // Diagonal should be (\) but it's actually (/)
-			default:
-				slopeColor = color.NRGBA{R: 255, G: 255, B: 255, A: 255}
-			}
-			newImage.Set(x, y, slopeColor)
+			newImage.SetNRGBA(x, y, gradientOrientation(sumX, sumY).color())
 		}
 	}
 	return newImage
 }
 
-func SobelOperatorColored(im image.Image, threshold float64) *image.NRGBA {
+// SobelMagnitudes returns gradient magnitudes of every pixel of a grayscale image
+func SobelMagnitudes(im image.Image, border Border) *Magnitudes {
+	bounds := im.Bounds()
+	magnitudes := NewMagnitudes(bounds)
+	radius := len(sobelKernelHorizontal) / 2
+	w := new(sync.WaitGroup)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		w.Add(1)
+		go func(y int) {
+			defer w.Done()
+			for x := bounds.Min.X; x < bounds.Max.X; x++ {
+				var sumX int
+				var sumY int
+				for ky := -radius; ky <= radius; ky++ {
+					for kx := -radius; kx <= radius; kx++ {
+						r, _, _, _ := border.at(im, x+kx, y+ky).RGBA()
+						sumX += sobelKernelHorizontal[ky+radius][kx+radius] * int(r)
+						sumY += sobelKernelVertical[ky+radius][kx+radius] * int(r)
+					}
+				}
+				magnitudes.Set(x, y, math.Sqrt(float64(sumX*sumX+sumY*sumY)))
+			}
+		}(y)
+	}
+	w.Wait()
+	return magnitudes
+}
+
+func SobelOperatorColored(im image.Image, threshold float64, border Border) *image.NRGBA {
 	bounds := im.Bounds()
 	newImage := image.NewNRGBA(bounds)
 	radius := len(sobelKernelHorizontal) / 2
@@ -289,9 +278,7 @@ func SobelOperatorColored(im image.Image, threshold float64) *image.NRGBA {
 			var sumY int
 			for ky := -radius; ky <= radius; ky++ {
 				for kx := -radius; kx <= radius; kx++ {
-					clampedX := clampToBorders(x+kx, bounds.Min.X, bounds.Max.X)
-					clampedY := clampToBorders(y+ky, bounds.Min.Y, bounds.Max.Y)
-					r, _, _, _ := im.At(clampedX, clampedY).RGBA()
+					r, _, _, _ := border.at(im, x+kx, y+ky).RGBA()
 					sumX += sobelKernelHorizontal[ky+radius][kx+radius] * int(r)
 					sumY += sobelKernelVertical[ky+radius][kx+radius] * int(r)
 
@@ -312,25 +299,16 @@ func SobelOperatorColored(im image.Image, threshold float64) *image.NRGBA {
 	return newImage
 }
 
-func AsciiAddColors(im image.Image, art [][]string, scale int) [][]string {
-	bounds := im.Bounds()
-	resized := imaging.Resize(im, bounds.Max.X/scale, bounds.Max.Y/scale, imaging.NearestNeighbor)
-	resized = imaging.Resize(im, bounds.Max.X, bounds.Max.Y, imaging.NearestNeighbor)
+func AsciiAddColors(im image.Image, art *canvas.Canvas, grid Grid, sampler Sampler) *canvas.Canvas {
 	w := &sync.WaitGroup{}
-	for y := bounds.Min.Y; y < bounds.Max.Y; y += 8 {
+	for row := 0; row < grid.Rows; row++ {
 		w.Add(1)
-		go func(y int) {
+		go func(row int) {
 			defer w.Done()
-			for x := bounds.Min.X; x < bounds.Max.X; x += 8 {
-				if art[y/scale][x/scale] == "\n" {
-					continue
-				}
-				var r, g, b uint32
-				r, g, b, _ = resized.At(x, y).RGBA()
-				r, g, b = r>>8, g>>8, b>>8
-				art[y/scale][x/scale] = fmt.Sprintf("<span style='color: rgb(%d, %d, %d);'>%s</span>", r, g, b, art[y/scale][x/scale])
+			for col := 0; col < grid.Columns; col++ {
+				art.At(col, row).FG = sampler.sample(im, grid.Block(col, row))
 			}
-		}(y)
+		}(row)
 	}
 	w.Wait()
 	return art
@@ -0,0 +1,48 @@
+package effects
+
+import (
+	"ascii/canvas"
+	"image"
+)
+
+// Grid maps cells of the art to blocks of pixels of the image. Cells in the
+// last column and row can be smaller when the image size isn't a multiple of
+// the cell size
+type Grid struct {
+	Bounds     image.Rectangle
+	CellWidth  int
+	CellHeight int
+	Columns    int
+	Rows       int
+}
+
+func NewGrid(bounds image.Rectangle, cellWidth, cellHeight int) Grid {
+	return Grid{
+		Bounds:     bounds,
+		CellWidth:  cellWidth,
+		CellHeight: cellHeight,
+		Columns:    (bounds.Dx() + cellWidth - 1) / cellWidth,
+		Rows:       (bounds.Dy() + cellHeight - 1) / cellHeight,
+	}
+}
+
+// Block returns pixels of the cell in the image coordinates
+func (g Grid) Block(col, row int) image.Rectangle {
+	min := g.Bounds.Min.Add(image.Pt(col*g.CellWidth, row*g.CellHeight))
+	return image.Rectangle{Min: min, Max: min.Add(image.Pt(g.CellWidth, g.CellHeight))}.Intersect(g.Bounds)
+}
+
+func (g Grid) NewCanvas() *canvas.Canvas {
+	return canvas.New(g.Columns, g.Rows)
+}
+
+// SubBlock splits the cell into columns x rows parts and returns the part i, j
+// in the image coordinates, it is empty when the part is outside of the image
+func (g Grid) SubBlock(col, row, i, j, columns, rows int) image.Rectangle {
+	min := g.Bounds.Min.Add(image.Pt(col*g.CellWidth, row*g.CellHeight))
+	r := image.Rect(
+		min.X+g.CellWidth*i/columns, min.Y+g.CellHeight*j/rows,
+		min.X+g.CellWidth*(i+1)/columns, min.Y+g.CellHeight*(j+1)/rows,
+	)
+	return r.Intersect(g.Bounds)
+}
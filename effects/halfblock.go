@@ -0,0 +1,34 @@
+package effects
+
+import (
+	"ascii/canvas"
+	"image"
+	"sync"
+)
+
+// halfBlockFill draws every cell as ▀, the top half of the block is the
+// foreground color and the bottom half is the background color
+func halfBlockFill(im image.Image, art *canvas.Canvas, grid Grid, sampler Sampler) {
+	w := new(sync.WaitGroup)
+	for row := 0; row < grid.Rows; row++ {
+		w.Add(1)
+		go func(row int) {
+			defer w.Done()
+			for col := 0; col < grid.Columns; col++ {
+				block := grid.Block(col, row)
+				top, bottom := block, block
+				if block.Dy() > 1 {
+					middle := block.Min.Y + block.Dy()/2
+					top.Max.Y = middle
+					bottom.Min.Y = middle
+				}
+				c := art.At(col, row)
+				c.Rune = '▀'
+				c.FG = sampler.sample(im, top)
+				c.BG = sampler.sample(im, bottom)
+				c.Kind = canvas.Fill
+			}
+		}(row)
+	}
+	w.Wait()
+}
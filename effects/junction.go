@@ -0,0 +1,110 @@
+package effects
+
+import "image"
+
+// sides of a block for arms of junctions
+const (
+	sideUp = iota
+	sideRight
+	sideDown
+	sideLeft
+)
+
+// junctionRatio is how many pixels the second orientation of a cell needs
+// compared to the first one to make a junction
+const junctionRatio = 1.0 / 3
+
+// junctionLetters are junctions of horizontal and vertical edges by their
+// arms up, right, down and left
+var junctionLetters = map[[4]bool]rune{
+	{false, true, true, false}: '┌',
+	{false, false, true, true}: '┐',
+	{true, true, false, false}: '└',
+	{true, false, false, true}: '┘',
+	{true, true, true, false}:  '├',
+	{true, false, true, true}:  '┤',
+	{false, true, true, true}:  '┬',
+	{true, true, false, true}:  '┴',
+	{true, true, true, true}:   '┼',
+}
+
+// edgeArms counts edge pixels in the outer quarters of a block, horizontal
+// edges on the left and right, other edges on the top and bottom
+type edgeArms struct {
+	block image.Rectangle
+	sides [edgeOrientations][4]int
+}
+
+func (a *edgeArms) add(o EdgeOrientation, x, y int) {
+	quarterX, quarterY := max(a.block.Dx()/4, 1), max(a.block.Dy()/4, 1)
+	sides := &a.sides[o]
+	if o == EdgeHorizontal {
+		if x < a.block.Min.X+quarterX {
+			sides[sideLeft]++
+		}
+		if x >= a.block.Max.X-quarterX {
+			sides[sideRight]++
+		}
+		return
+	}
+	if y < a.block.Min.Y+quarterY {
+		sides[sideUp]++
+	}
+	if y >= a.block.Max.Y-quarterY {
+		sides[sideDown]++
+	}
+}
+
+// hasArm tells if a side has at least an eighth of the pixels of the edge
+func (a *edgeArms) hasArm(o EdgeOrientation, side, sum int) bool {
+	return a.sides[o][side] > 0 && a.sides[o][side]*8 >= sum
+}
+
+// junction returns a letter for cells where two edges meet or 0 when the cell
+// has one edge. Horizontal and vertical edges make corners, T-junctions and
+// crossings, crossed diagonals make x
+func (a *edgeArms) junction(counts [edgeOrientations]int, threshold int, edges EdgeSet) rune {
+	first, second := EdgeNone, EdgeNone
+	for o := EdgeHorizontal; o < edgeOrientations; o++ {
+		switch {
+		case first == EdgeNone || counts[o] > counts[first]:
+			first, second = o, first
+		case second == EdgeNone || counts[o] > counts[second]:
+			second = o
+		}
+	}
+	if counts[second] < threshold || float64(counts[second]) < junctionRatio*float64(counts[first]) {
+		return 0
+	}
+	pair := [2]EdgeOrientation{min(first, second), max(first, second)}
+	switch pair {
+	case [2]EdgeOrientation{EdgeHorizontal, EdgeVertical}:
+		arms := [4]bool{
+			a.hasArm(EdgeVertical, sideUp, counts[EdgeVertical]),
+			a.hasArm(EdgeHorizontal, sideRight, counts[EdgeHorizontal]),
+			a.hasArm(EdgeVertical, sideDown, counts[EdgeVertical]),
+			a.hasArm(EdgeHorizontal, sideLeft, counts[EdgeHorizontal]),
+		}
+		letter, ok := junctionLetters[arms]
+		if !ok {
+			return 0
+		}
+		if edges == EdgeUnicode {
+			return letter
+		}
+		return '+'
+	case [2]EdgeOrientation{EdgeFalling, EdgeRising}:
+		// a crossing goes through the whole cell, V and Λ shapes stay single edges
+		sum := counts[EdgeFalling] + counts[EdgeRising]
+		for _, o := range pair {
+			if !a.hasArm(o, sideUp, sum) || !a.hasArm(o, sideDown, sum) {
+				return 0
+			}
+		}
+		if edges == EdgeUnicode {
+			return '╳'
+		}
+		return 'x'
+	}
+	return 0
+}
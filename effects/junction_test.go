@@ -0,0 +1,72 @@
+package effects
+
+import (
+	"ascii/canvas"
+	"context"
+	"image"
+	"image/color"
+	"math"
+	"testing"
+)
+
+type segment struct {
+	x0, y0, x1, y1 float64
+}
+
+// segmentImage draws white lines 3 pixels wide on black
+func segmentImage(size int, segments []segment) *image.NRGBA {
+	im := image.NewNRGBA(image.Rect(0, 0, size, size))
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			clr := color.NRGBA{A: 255}
+			px, py := float64(x)+0.5, float64(y)+0.5
+			for _, s := range segments {
+				dx, dy := s.x1-s.x0, s.y1-s.y0
+				t := math.Max(0, math.Min(1, ((px-s.x0)*dx+(py-s.y0)*dy)/(dx*dx+dy*dy)))
+				if math.Hypot(px-s.x0-t*dx, py-s.y0-t*dy) < 1.5 {
+					clr = color.NRGBA{R: 255, G: 255, B: 255, A: 255}
+				}
+			}
+			im.SetNRGBA(x, y, clr)
+		}
+	}
+	return im
+}
+
+func TestJunctions(t *testing.T) {
+	// lines meet in the middle of the cell 4, 4 of 16x16 cells
+	const size, center = 160, 72.0
+	tests := []struct {
+		name     string
+		segments []segment
+		ascii    rune
+		unicode  rune
+	}{
+		{"L", []segment{{center, center, size, center}, {center, center, center, size}}, '+', '┌'},
+		{"T", []segment{{center, 0, center, size}, {center, center, size, center}}, '+', '├'},
+		{"+", []segment{{center, 0, center, size}, {0, center, size, center}}, '+', '┼'},
+		{"X", []segment{{0, 0, size, size}, {0, 2 * center, 2 * center, 0}}, 'x', '╳'},
+		// V is two edges meeting at the bottom, not a crossing
+		{"V", []segment{{center - 60, center - 60, center, center}, {center, center, center + 60, center - 60}}, '\\', '╲'},
+	}
+	for _, tt := range tests {
+		for _, edges := range []EdgeSet{EdgeASCII, EdgeUnicode} {
+			want := tt.ascii
+			if edges == EdgeUnicode {
+				want = tt.unicode
+			}
+			opts := DefaultOptions()
+			opts.CellWidth = 16
+			opts.Edges = edges
+			opts.Junctions = true
+			res, err := Render(context.Background(), segmentImage(size, tt.segments), opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			cell := res.Canvas.At(4, 4)
+			if cell.Kind != canvas.Edge || cell.Rune != want {
+				t.Errorf("%s: got %q, want %q", tt.name, cell.Rune, want)
+			}
+		}
+	}
+}
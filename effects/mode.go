@@ -0,0 +1,60 @@
+package effects
+
+import "fmt"
+
+// Mode is the way cells get their letters
+type Mode int
+
+const (
+	// ModeRamp draws edges with | _ / \ and fills the rest by luminance from the ramp
+	ModeRamp Mode = iota
+	// ModeShape chooses the glyph of the charset that looks the most like the cell
+	ModeShape
+	// ModeHalfBlock draws ▀ with the top half of the cell as the foreground color
+	// and the bottom half as the background color
+	ModeHalfBlock
+	// ModeBraille draws 2x4 dots per cell with braille patterns
+	ModeBraille
+	// ModeQuadrant draws 2x2 block elements with two colors per cell
+	ModeQuadrant
+	// ModeSextant draws 2x3 block elements of Unicode 13 with two colors per cell
+	ModeSextant
+)
+
+func ParseMode(s string) (Mode, error) {
+	switch s {
+	case "ramp":
+		return ModeRamp, nil
+	case "shape":
+		return ModeShape, nil
+	case "halfblock":
+		return ModeHalfBlock, nil
+	case "braille":
+		return ModeBraille, nil
+	case "quadrant":
+		return ModeQuadrant, nil
+	case "sextant":
+		return ModeSextant, nil
+	}
+	return ModeRamp, fmt.Errorf("unknown mode %q", s)
+}
+
+// Metric compares a cell with a glyph in ModeShape
+type Metric int
+
+const (
+	// MetricSSIM is the structural similarity, it also compares brightness
+	MetricSSIM Metric = iota
+	// MetricNCC is the normalized cross-correlation, it compares only the shape
+	MetricNCC
+)
+
+func ParseMetric(s string) (Metric, error) {
+	switch s {
+	case "ssim":
+		return MetricSSIM, nil
+	case "ncc":
+		return MetricNCC, nil
+	}
+	return MetricSSIM, fmt.Errorf("unknown metric %q", s)
+}
@@ -0,0 +1,143 @@
+package effects
+
+import (
+	"ascii/canvas"
+	"image"
+	"image/color"
+	"math"
+	"sync"
+)
+
+// quadrantLetters are indexed by a mask of the 2x2 parts, the bit 1 is the
+// upper left part, then row by row
+var quadrantLetters = [16]rune{
+	' ', '▘', '▝', '▀', '▖', '▌', '▞', '▛', '▗', '▚', '▐', '▜', '▄', '▙', '▟', '█',
+}
+
+// sextantLetter returns the letter of a mask of the 2x3 parts in the same
+// order. Unicode 13 sextants skip the masks of the existing half blocks
+func sextantLetter(mask int) rune {
+	switch mask {
+	case 0:
+		return ' '
+	case 0b010101:
+		return '▌'
+	case 0b101010:
+		return '▐'
+	case 0b111111:
+		return '█'
+	}
+	r := rune(0x1fb00 + mask - 1)
+	if mask > 0b010101 {
+		r--
+	}
+	if mask > 0b101010 {
+		r--
+	}
+	return r
+}
+
+// mosaicFill splits every cell into columns x rows parts and draws the block
+// element whose mask separates the colors of the parts the best, the parts
+// under the mask are the foreground color and the rest is the background
+func mosaicFill(im image.Image, art *canvas.Canvas, grid Grid, columns, rows int, letter func(mask int) rune) {
+	parts := columns * rows
+	w := new(sync.WaitGroup)
+	for row := 0; row < grid.Rows; row++ {
+		w.Add(1)
+		go func(row int) {
+			defer w.Done()
+			means := make([][3]float64, parts)
+			counts := make([]float64, parts)
+			for col := 0; col < grid.Columns; col++ {
+				for j := 0; j < rows; j++ {
+					for i := 0; i < columns; i++ {
+						part := j*columns + i
+						means[part], counts[part] = meanColor(im, grid.SubBlock(col, row, i, j, columns, rows))
+					}
+				}
+				var bestMask int
+				var fg, bg [3]float64
+				bestError := math.Inf(1)
+				// a mask and its complement make the same picture, so the last part
+				// always belongs to the foreground. The full block goes first to win
+				// for cells of one color
+				for mask := 1<<parts - 1; mask >= 1<<(parts-1); mask-- {
+					maskFG := clusterMean(means, counts, mask, true)
+					maskBG := clusterMean(means, counts, mask, false)
+					var e float64
+					for part := range means {
+						center := maskBG
+						if mask&(1<<part) != 0 {
+							center = maskFG
+						}
+						e += counts[part] * colorDistance(means[part], center)
+					}
+					if e < bestError {
+						bestError, bestMask, fg, bg = e, mask, maskFG, maskBG
+					}
+				}
+				if bestMask == 1<<parts-1 {
+					bg = fg
+				}
+				c := art.At(col, row)
+				c.Rune = letter(bestMask)
+				c.FG = toNRGBA(fg)
+				c.BG = toNRGBA(bg)
+				c.Kind = canvas.Fill
+			}
+		}(row)
+	}
+	w.Wait()
+}
+
+// meanColor returns the mean color of the block and its amount of pixels
+func meanColor(im image.Image, block image.Rectangle) ([3]float64, float64) {
+	var sum [3]float64
+	for y := block.Min.Y; y < block.Max.Y; y++ {
+		for x := block.Min.X; x < block.Max.X; x++ {
+			r, g, b, _ := im.At(x, y).RGBA()
+			sum[0] += float64(r >> 8)
+			sum[1] += float64(g >> 8)
+			sum[2] += float64(b >> 8)
+		}
+	}
+	n := float64(block.Dx() * block.Dy())
+	if n == 0 {
+		return sum, 0
+	}
+	return [3]float64{sum[0] / n, sum[1] / n, sum[2] / n}, n
+}
+
+// clusterMean returns the mean color of the parts inside of the mask or outside of it
+func clusterMean(means [][3]float64, counts []float64, mask int, inside bool) [3]float64 {
+	var sum [3]float64
+	var n float64
+	for part := range means {
+		if (mask&(1<<part) != 0) != inside {
+			continue
+		}
+		for i := range sum {
+			sum[i] += counts[part] * means[part][i]
+		}
+		n += counts[part]
+	}
+	if n == 0 {
+		return sum
+	}
+	return [3]float64{sum[0] / n, sum[1] / n, sum[2] / n}
+}
+
+func colorDistance(a, b [3]float64) float64 {
+	dr, dg, db := a[0]-b[0], a[1]-b[1], a[2]-b[2]
+	return dr*dr + dg*dg + db*db
+}
+
+func toNRGBA(c [3]float64) color.NRGBA {
+	return color.NRGBA{
+		R: clamp(int(math.Round(c[0]))),
+		G: clamp(int(math.Round(c[1]))),
+		B: clamp(int(math.Round(c[2]))),
+		A: 255,
+	}
+}
@@ -0,0 +1,86 @@
+package effects
+
+import (
+	"ascii/font"
+	"ascii/pixel"
+	"math"
+)
+
+// DefaultKernelRadius is the radius of gaussian kernels in sigmas,
+// 3 sigmas on each side keep 99.7% of the weight
+const DefaultKernelRadius = 3.0
+
+type Options struct {
+	Mode  Mode
+	Sigma float64
+	K     float64
+	// radius of gaussian kernels in sigmas
+	KernelRadius   float64
+	DoGThreshold   int
+	SobelThreshold float64
+	EdgeThreshold  int
+	// Edges is the set of edge letters. HeavyThreshold is the mean sobel
+	// magnitude of the grayscale image over the edge pixels of a cell for heavy
+	// box-drawing letters, a step from black to white is 262140
+	Edges          EdgeSet
+	HeavyThreshold float64
+	// Junctions draws corners and crossings where edges meet
+	Junctions bool
+	Border    Border
+	CellWidth int
+	// 0 means the same as CellWidth
+	CellHeight int
+	// CharAspect is height/width of a font glyph, when it is set the cell
+	// height is CellWidth*CharAspect so letters aren't stretched vertically
+	CharAspect float64
+	// Columns and Rows resize the image before filters so the art fits
+	// into them keeping the aspect ratio, 0 means no limit
+	Columns int
+	Rows    int
+	// Luminance is used for the grayscale image and to choose fill letters
+	Luminance pixel.LuminanceModel
+	// Sampler chooses the luminance and the color of a cell from its pixels
+	Sampler   Sampler
+	AddColors bool
+	// Ramp is ordered from the lightest letter to the densest,
+	// dark cells get the first letters on a dark background
+	Ramp []rune
+	// Invert reverses the ramp for light backgrounds
+	Invert bool
+	// Dither sets the dots of ModeBraille by ordered dithering of the image
+	// instead of the difference of gaussians
+	Dither bool
+	// Font and Charset are the glyphs ModeShape chooses from,
+	// nil means font.Basic() and printable ASCII
+	Font    *font.Font
+	Charset []rune
+	Metric  Metric
+	Output  string
+}
+
+func DefaultOptions() Options {
+	return Options{
+		Sigma:          0.5,
+		K:              6,
+		KernelRadius:   DefaultKernelRadius,
+		DoGThreshold:   120,
+		SobelThreshold: 1200,
+		EdgeThreshold:  4,
+		HeavyThreshold: 100000,
+		CellWidth:      8,
+		AddColors:      false,
+		Ramp:           DefaultRamp,
+		Output:         "ascii-result.html",
+	}
+}
+
+func (o Options) cellSize() (int, int) {
+	width, height := o.CellWidth, o.CellHeight
+	if o.CharAspect > 0 {
+		height = int(math.Round(float64(width) * o.CharAspect))
+	}
+	if height == 0 {
+		height = width
+	}
+	return width, height
+}
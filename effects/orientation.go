@@ -0,0 +1,79 @@
+package effects
+
+import (
+	"image/color"
+	"math"
+)
+
+// EdgeOrientation is the direction of an edge on the screen, y goes down
+type EdgeOrientation int
+
+const (
+	EdgeNone EdgeOrientation = iota
+	// EdgeHorizontal is _
+	EdgeHorizontal
+	// EdgeVertical is |
+	EdgeVertical
+	// EdgeFalling goes down to the right, \
+	EdgeFalling
+	// EdgeRising goes up to the right, /
+	EdgeRising
+	edgeOrientations
+)
+
+// gradientOrientation returns the orientation of the edge of a sobel gradient.
+// The edge is perpendicular to the gradient: a gradient to the lower right
+// (45°) is an edge rising to the right, a gradient to the lower left (135°) is
+// an edge falling to the right
+func gradientOrientation(sumX, sumY int) EdgeOrientation {
+	angle := math.Atan2(float64(sumY), float64(sumX))
+	normalizedAngle := (angle + math.Pi) / (2 * math.Pi) // Normalize to 0-1
+	normalizedAngle = math.Mod(normalizedAngle*360, 180) // Normalize to 0-180 degrees
+	switch {
+	case normalizedAngle < 22.5 || normalizedAngle >= 157.5:
+		return EdgeVertical
+	case normalizedAngle < 67.5:
+		return EdgeRising
+	case normalizedAngle < 112.5:
+		return EdgeHorizontal
+	}
+	return EdgeFalling
+}
+
+// orientationColors are the colors of edge pixels in SobelOperatorAngleColored
+var orientationColors = [edgeOrientations]color.NRGBA{
+	EdgeNone:       {R: 0, G: 0, B: 0, A: 255},
+	EdgeHorizontal: {R: 255, G: 0, B: 0, A: 255},
+	EdgeVertical:   {R: 0, G: 0, B: 255, A: 255},
+	EdgeFalling:    {R: 0, G: 255, B: 0, A: 255},
+	EdgeRising:     {R: 255, G: 255, B: 0, A: 255},
+}
+
+func (o EdgeOrientation) color() color.NRGBA {
+	return orientationColors[o]
+}
+
+// colorOrientation returns the orientation of a pixel of SobelOperatorAngleColored
+func colorOrientation(c color.NRGBA) EdgeOrientation {
+	for o, clr := range orientationColors {
+		if c == clr {
+			return EdgeOrientation(o)
+		}
+	}
+	return EdgeNone
+}
+
+// letter returns the ASCII letter of the orientation
+func (o EdgeOrientation) letter() rune {
+	switch o {
+	case EdgeHorizontal:
+		return '_'
+	case EdgeVertical:
+		return '|'
+	case EdgeFalling:
+		return '\\'
+	case EdgeRising:
+		return '/'
+	}
+	return 0
+}
@@ -0,0 +1,94 @@
+package effects
+
+import (
+	"ascii/canvas"
+	"context"
+	"image"
+	"image/color"
+	"math"
+	"testing"
+)
+
+func TestGradientOrientation(t *testing.T) {
+	// y goes down, so a gradient to the lower right crosses a rising edge
+	tests := []struct {
+		sumX, sumY int
+		want       EdgeOrientation
+	}{
+		{1000, 0, EdgeVertical},
+		{-1000, 0, EdgeVertical},
+		{0, 1000, EdgeHorizontal},
+		{0, -1000, EdgeHorizontal},
+		{1000, 1000, EdgeRising},
+		{-1000, -1000, EdgeRising},
+		{-1000, 1000, EdgeFalling},
+		{1000, -1000, EdgeFalling},
+	}
+	for _, tt := range tests {
+		if got := gradientOrientation(tt.sumX, tt.sumY); got != tt.want {
+			t.Errorf("gradientOrientation(%d, %d) = %v, want %v", tt.sumX, tt.sumY, got, tt.want)
+		}
+	}
+}
+
+// lineImage draws a white line through the center of a black image, angle is
+// counterclockwise on the screen from the x axis in degrees
+func lineImage(angle float64) *image.NRGBA {
+	bounds := image.Rect(0, 0, 160, 160)
+	im := image.NewNRGBA(bounds)
+	dx, dy := math.Cos(angle*math.Pi/180), -math.Sin(angle*math.Pi/180)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			px, py := float64(x)-80, float64(y)-80
+			clr := color.NRGBA{A: 255}
+			if math.Abs(px*dy-py*dx) < 3 {
+				clr = color.NRGBA{R: 255, G: 255, B: 255, A: 255}
+			}
+			im.SetNRGBA(x, y, clr)
+		}
+	}
+	return im
+}
+
+func TestRenderLineOrientation(t *testing.T) {
+	tests := []struct {
+		angle   float64
+		ascii   rune
+		unicode rune
+	}{
+		{0, '_', '─'},
+		{45, '/', '╱'},
+		{90, '|', '│'},
+		{135, '\\', '╲'},
+	}
+	for _, tt := range tests {
+		for _, edges := range []EdgeSet{EdgeASCII, EdgeUnicode} {
+			want := tt.ascii
+			if edges == EdgeUnicode {
+				want = tt.unicode
+			}
+			opts := DefaultOptions()
+			opts.Edges = edges
+			// light letters only, the line is white on black
+			opts.HeavyThreshold = math.Inf(1)
+			res, err := Render(context.Background(), lineImage(tt.angle), opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			counts := make(map[rune]int)
+			for _, cell := range res.Canvas.Cells {
+				if cell.Kind == canvas.Edge {
+					counts[cell.Rune]++
+				}
+			}
+			for r, n := range counts {
+				if n > counts[want] {
+					t.Errorf("%v°: %q in %d cells, more than %q in %d", tt.angle, r, n, want, counts[want])
+				}
+			}
+			if counts[want] == 0 {
+				t.Errorf("%v°: no %q edges", tt.angle, want)
+			}
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package effects
+
+import (
+	"ascii/font"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+var DefaultRamp = []rune(" .:-=+*#%@")
+
+var Ramps = map[string][]rune{
+	"short": DefaultRamp,
+	// Paul Bourke's 70 levels of gray
+	"long":   []rune(" .'`^\",:;Il!i><~+_-?][}{1)(|\\/tfjrxnuvczXYUJCLQ0OZmwqpdbkhao*#MW&8%B@$"),
+	"blocks": []rune(" ▁▂▃▄▅▆▇█"),
+	"shades": []rune(" ░▒▓█"),
+	// all printable ASCII letters ordered by their density in the built-in font
+	"ascii": font.DensityRamp(font.Basic(), font.PrintableASCII(), 0),
+}
+
+// ParseRamp returns the built-in ramp with this name or letters of s
+func ParseRamp(s string) []rune {
+	if ramp, ok := Ramps[s]; ok {
+		return ramp
+	}
+	return []rune(s)
+}
+
+func ReadRampFile(filename string) ([]rune, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	ramp := []rune(strings.TrimRight(string(data), "\r\n"))
+	if len(ramp) == 0 {
+		return nil, fmt.Errorf("ramp file %s is empty", filename)
+	}
+	return ramp, nil
+}
+
+func RampNames() []string {
+	names := make([]string, 0, len(Ramps))
+	for name := range Ramps {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// rampLetter quantizes luminance from 0 to 1 to a letter of the ramp
+func rampLetter(ramp []rune, luminance float64, invert bool) rune {
+	if invert {
+		luminance = 1 - luminance
+	}
+	i := int(luminance * float64(len(ramp)))
+	return ramp[max(0, min(i, len(ramp)-1))]
+}
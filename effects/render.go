@@ -0,0 +1,138 @@
+package effects
+
+import (
+	"ascii/canvas"
+	"ascii/font"
+	"ascii/pixel"
+	"context"
+	"errors"
+	"image"
+	"math"
+	"sync"
+
+	"github.com/disintegration/imaging"
+)
+
+type Result struct {
+	Canvas *canvas.Canvas
+}
+
+func Render(ctx context.Context, im image.Image, opts Options) (*Result, error) {
+	cellWidth, cellHeight := opts.cellSize()
+	if cellWidth <= 0 || cellHeight <= 0 {
+		return nil, errors.New("cell size must be positive")
+	}
+	if len(opts.Ramp) == 0 {
+		return nil, errors.New("ramp must not be empty")
+	}
+	if opts.Sigma <= 0 || opts.K <= 0 {
+		return nil, errors.New("sigma and k must be positive")
+	}
+	if opts.KernelRadius < 0 {
+		return nil, errors.New("kernel radius must not be negative")
+	}
+	if opts.Columns > 0 || opts.Rows > 0 {
+		im = fitImage(im, opts.Columns, opts.Rows, cellWidth, cellHeight)
+	}
+	grid := NewGrid(im.Bounds(), cellWidth, cellHeight)
+	// block elements are colored pixels, they don't need edges
+	switch opts.Mode {
+	case ModeHalfBlock:
+		art := grid.NewCanvas()
+		halfBlockFill(im, art, grid, opts.Sampler)
+		return &Result{Canvas: art}, ctx.Err()
+	case ModeQuadrant:
+		art := grid.NewCanvas()
+		mosaicFill(im, art, grid, 2, 2, func(mask int) rune { return quadrantLetters[mask] })
+		return &Result{Canvas: art}, ctx.Err()
+	case ModeSextant:
+		art := grid.NewCanvas()
+		mosaicFill(im, art, grid, 2, 3, sextantLetter)
+		return &Result{Canvas: art}, ctx.Err()
+	}
+
+	grayscaleImage := Grayscale(im, opts.Luminance)
+	dogImage := GaussianDifference(grayscaleImage, opts.Sigma, opts.K, opts.KernelRadius, opts.DoGThreshold, opts.Border)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	bordersImage := SobelOperatorAngleColored(dogImage, opts.SobelThreshold, opts.Border)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	// heavy edges are chosen by the contrast of the image, the difference of
+	// gaussians is only black and white
+	var magnitudes *Magnitudes
+	if opts.Edges == EdgeUnicode {
+		magnitudes = SobelMagnitudes(grayscaleImage, opts.Border)
+	}
+	var art *canvas.Canvas
+	switch opts.Mode {
+	case ModeBraille:
+		art = grid.NewCanvas()
+		brailleFill(bordersImage, dogImage, grayscaleImage, art, grid, opts.Invert, opts.Dither)
+	case ModeShape:
+		art = AsciiBorders(bordersImage, magnitudes, opts.EdgeThreshold, grid, opts.Edges, opts.HeavyThreshold, opts.Junctions)
+		f := opts.Font
+		if f == nil {
+			f = font.Basic()
+		}
+		charset := opts.Charset
+		if len(charset) == 0 {
+			charset = font.PrintableASCII()
+		}
+		if err := shapeFill(bordersImage, grayscaleImage, art, grid, f, charset, opts.Ramp, opts.Invert, opts.Metric); err != nil {
+			return nil, err
+		}
+	default:
+		art = AsciiBorders(bordersImage, magnitudes, opts.EdgeThreshold, grid, opts.Edges, opts.HeavyThreshold, opts.Junctions)
+		asciiFill(im, art, opts.Ramp, opts.Invert, grid, opts.Sampler, opts.Luminance)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if opts.AddColors {
+		AsciiAddColors(im, art, grid, opts.Sampler)
+	}
+	return &Result{Canvas: art}, nil
+}
+
+func asciiFill(im image.Image, art *canvas.Canvas, ramp []rune, invert bool, grid Grid, sampler Sampler, model pixel.LuminanceModel) {
+	w := new(sync.WaitGroup)
+	for row := 0; row < grid.Rows; row++ {
+		w.Add(1)
+		go func(row int) {
+			defer w.Done()
+			for col := 0; col < grid.Columns; col++ {
+				c := art.At(col, row)
+				if c.Rune != 0 {
+					continue
+				}
+				pixelColor := sampler.sample(im, grid.Block(col, row))
+				c.Rune = rampLetter(ramp, model.Luminance(pixelColor), invert)
+				c.Kind = canvas.Fill
+			}
+		}(row)
+	}
+	w.Wait()
+}
+
+// fitImage resizes the image so the grid has at most columns and rows cells.
+// Cells keep their size in pixels, so thresholds of the filters mean the same
+// for the resized image as for an image that already had this size
+func fitImage(im image.Image, columns, rows, cellWidth, cellHeight int) image.Image {
+	bounds := im.Bounds()
+	scale := math.Inf(1)
+	if columns > 0 {
+		scale = float64(columns*cellWidth) / float64(bounds.Dx())
+	}
+	if rows > 0 {
+		scale = math.Min(scale, float64(rows*cellHeight)/float64(bounds.Dy()))
+	}
+	width := max(1, int(math.Round(float64(bounds.Dx())*scale)))
+	height := max(1, int(math.Round(float64(bounds.Dy())*scale)))
+	if width == bounds.Dx() && height == bounds.Dy() {
+		return im
+	}
+	return imaging.Resize(im, width, height, imaging.Lanczos)
+}
@@ -0,0 +1,117 @@
+package effects
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"slices"
+)
+
+// Sampler chooses one color for the block of pixels of a cell
+type Sampler int
+
+const (
+	SampleMean Sampler = iota
+	SampleMedian
+	// SampleCenter takes the pixel in the middle of the block
+	SampleCenter
+	// SampleMax takes the brightest pixel
+	SampleMax
+	// SampleGaussian is the mean weighted by distance to the center of the block
+	SampleGaussian
+)
+
+func ParseSampler(s string) (Sampler, error) {
+	switch s {
+	case "mean":
+		return SampleMean, nil
+	case "median":
+		return SampleMedian, nil
+	case "center":
+		return SampleCenter, nil
+	case "max":
+		return SampleMax, nil
+	case "gaussian":
+		return SampleGaussian, nil
+	}
+	return SampleMean, fmt.Errorf("unknown sampler %q", s)
+}
+
+func (s Sampler) sample(im image.Image, block image.Rectangle) color.NRGBA {
+	switch s {
+	case SampleCenter:
+		center := block.Min.Add(block.Size().Div(2))
+		return color.NRGBAModel.Convert(im.At(center.X, center.Y)).(color.NRGBA)
+	case SampleMax:
+		return sampleMax(im, block)
+	case SampleMedian:
+		return sampleMedian(im, block)
+	case SampleGaussian:
+		return sampleWeighted(im, block, true)
+	}
+	return sampleWeighted(im, block, false)
+}
+
+func sampleWeighted(im image.Image, block image.Rectangle, gaussian bool) color.NRGBA {
+	// sigma is a quarter of the block so the corners still count a bit
+	sigmaX := math.Max(float64(block.Dx())/4, 0.5)
+	sigmaY := math.Max(float64(block.Dy())/4, 0.5)
+	centerX := float64(block.Min.X+block.Max.X-1) / 2
+	centerY := float64(block.Min.Y+block.Max.Y-1) / 2
+	var sumR, sumG, sumB, sumWeight float64
+	for y := block.Min.Y; y < block.Max.Y; y++ {
+		for x := block.Min.X; x < block.Max.X; x++ {
+			weight := 1.0
+			if gaussian {
+				dx := (float64(x) - centerX) / sigmaX
+				dy := (float64(y) - centerY) / sigmaY
+				weight = math.Exp(-(dx*dx + dy*dy) / 2)
+			}
+			r, g, b, _ := im.At(x, y).RGBA()
+			sumR += weight * float64(r>>8)
+			sumG += weight * float64(g>>8)
+			sumB += weight * float64(b>>8)
+			sumWeight += weight
+		}
+	}
+	return color.NRGBA{
+		R: clamp(int(math.Round(sumR / sumWeight))),
+		G: clamp(int(math.Round(sumG / sumWeight))),
+		B: clamp(int(math.Round(sumB / sumWeight))),
+		A: 255,
+	}
+}
+
+func sampleMedian(im image.Image, block image.Rectangle) color.NRGBA {
+	n := block.Dx() * block.Dy()
+	rs, gs, bs := make([]uint8, 0, n), make([]uint8, 0, n), make([]uint8, 0, n)
+	for y := block.Min.Y; y < block.Max.Y; y++ {
+		for x := block.Min.X; x < block.Max.X; x++ {
+			r, g, b, _ := im.At(x, y).RGBA()
+			rs = append(rs, uint8(r>>8))
+			gs = append(gs, uint8(g>>8))
+			bs = append(bs, uint8(b>>8))
+		}
+	}
+	slices.Sort(rs)
+	slices.Sort(gs)
+	slices.Sort(bs)
+	return color.NRGBA{R: rs[n/2], G: gs[n/2], B: bs[n/2], A: 255}
+}
+
+func sampleMax(im image.Image, block image.Rectangle) color.NRGBA {
+	var brightest color.NRGBA
+	maxLuma := -1
+	for y := block.Min.Y; y < block.Max.Y; y++ {
+		for x := block.Min.X; x < block.Max.X; x++ {
+			c := color.NRGBAModel.Convert(im.At(x, y)).(color.NRGBA)
+			luma := 299*int(c.R) + 587*int(c.G) + 114*int(c.B)
+			if luma > maxLuma {
+				brightest = c
+				maxLuma = luma
+			}
+		}
+	}
+	return brightest
+}
@@ -0,0 +1,162 @@
+package effects
+
+import (
+	"ascii/canvas"
+	"ascii/font"
+	"errors"
+	"image"
+	"image/color"
+	"math"
+	"sync"
+)
+
+// SSIM constants for values from 0 to 1
+const (
+	ssimC1 = 0.01 * 0.01
+	ssimC2 = 0.03 * 0.03
+)
+
+type glyphPattern struct {
+	r        rune
+	values   []float64
+	mean     float64
+	variance float64
+}
+
+func newGlyphPatterns(f *font.Font, charset []rune) []glyphPattern {
+	var patterns []glyphPattern
+	seen := make(map[rune]bool)
+	for _, r := range charset {
+		if seen[r] || !f.CanDraw(r) {
+			continue
+		}
+		seen[r] = true
+		g := f.Glyph(r)
+		values := make([]float64, len(g.Bits))
+		for i, b := range g.Bits {
+			if b {
+				values[i] = 1
+			}
+		}
+		mean, variance := meanVariance(values)
+		patterns = append(patterns, glyphPattern{r: r, values: values, mean: mean, variance: variance})
+	}
+	return patterns
+}
+
+// shapeFill sets the letter of every cell to the glyph most similar to the
+// cell. Edge cells are compared by their edge pixels and the rest by luminance
+func shapeFill(edges, grayscaleImage image.Image, art *canvas.Canvas, grid Grid, f *font.Font, charset, ramp []rune, invert bool, metric Metric) error {
+	patterns := newGlyphPatterns(f, charset)
+	if len(patterns) == 0 {
+		return errors.New("none of the charset letters are in the font")
+	}
+	w := new(sync.WaitGroup)
+	for row := 0; row < grid.Rows; row++ {
+		w.Add(1)
+		go func(row int) {
+			defer w.Done()
+			patch := make([]float64, f.Width*f.Height)
+			for col := 0; col < grid.Columns; col++ {
+				c := art.At(col, row)
+				block := grid.Block(col, row)
+				if c.Rune != 0 {
+					// edge pixels are ink on any background
+					cellPatch(patch, edges, block, f.Width, f.Height, edgeValue)
+				} else {
+					cellPatch(patch, grayscaleImage, block, f.Width, f.Height, grayValue)
+					c.Kind = canvas.Fill
+					if invert {
+						for i := range patch {
+							patch[i] = 1 - patch[i]
+						}
+					}
+				}
+				mean, variance := meanVariance(patch)
+				if metric == MetricNCC && variance < 1e-6 {
+					// flat cell has no shape to correlate with
+					c.Rune = rampLetter(ramp, mean, false)
+					continue
+				}
+				c.Rune = bestGlyph(patch, mean, variance, patterns, metric)
+			}
+		}(row)
+	}
+	w.Wait()
+	return nil
+}
+
+func bestGlyph(patch []float64, mean, variance float64, patterns []glyphPattern, metric Metric) rune {
+	best := patterns[0].r
+	bestScore := math.Inf(-1)
+	for _, p := range patterns {
+		covariance := 0.0
+		for i, v := range patch {
+			covariance += (v - mean) * (p.values[i] - p.mean)
+		}
+		covariance /= float64(len(patch))
+
+		var score float64
+		if metric == MetricNCC {
+			if p.variance == 0 {
+				continue
+			}
+			score = covariance / math.Sqrt(variance*p.variance)
+		} else {
+			score = (2*mean*p.mean + ssimC1) * (2*covariance + ssimC2) /
+				((mean*mean + p.mean*p.mean + ssimC1) * (variance + p.variance + ssimC2))
+		}
+		if score > bestScore {
+			best = p.r
+			bestScore = score
+		}
+	}
+	return best
+}
+
+// cellPatch resamples the block to width x height values from 0 to 1,
+// every value is the mean of the pixels it covers
+func cellPatch(patch []float64, im image.Image, block image.Rectangle, width, height int, value func(color.Color) float64) {
+	for py := 0; py < height; py++ {
+		y0 := block.Min.Y + py*block.Dy()/height
+		y1 := max(y0+1, block.Min.Y+(py+1)*block.Dy()/height)
+		for px := 0; px < width; px++ {
+			x0 := block.Min.X + px*block.Dx()/width
+			x1 := max(x0+1, block.Min.X+(px+1)*block.Dx()/width)
+			sum := 0.0
+			for y := y0; y < y1; y++ {
+				for x := x0; x < x1; x++ {
+					sum += value(im.At(x, y))
+				}
+			}
+			patch[py*width+px] = sum / float64((y1-y0)*(x1-x0))
+		}
+	}
+}
+
+func grayValue(c color.Color) float64 {
+	r, _, _, _ := c.RGBA()
+	return float64(r) / 65535
+}
+
+// edgeValue is 1 for any edge pixel of SobelOperatorAngleColored
+func edgeValue(c color.Color) float64 {
+	r, g, b, _ := c.RGBA()
+	if r == 0 && g == 0 && b == 0 {
+		return 0
+	}
+	return 1
+}
+
+func meanVariance(values []float64) (float64, float64) {
+	mean := 0.0
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+	variance := 0.0
+	for _, v := range values {
+		variance += (v - mean) * (v - mean)
+	}
+	return mean, variance / float64(len(values))
+}
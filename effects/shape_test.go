@@ -0,0 +1,64 @@
+package effects
+
+import (
+	"ascii/canvas"
+	"context"
+	"image"
+	"image/color"
+	"testing"
+)
+
+// boundaryImage is white with black pixels where dark is true
+func boundaryImage(width, height int, dark func(x, y int) bool) *image.NRGBA {
+	im := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			v := uint8(255)
+			if dark(x, y) {
+				v = 0
+			}
+			im.SetNRGBA(x, y, color.NRGBA{R: v, G: v, B: v, A: 255})
+		}
+	}
+	return im
+}
+
+func TestShapeInvertKeepsEdgeGlyphs(t *testing.T) {
+	// cells are the size of the built-in font so glyphs aren't resampled,
+	// the vertical edge goes through the middle column of the cells and the
+	// diagonal one through their corners
+	tests := []struct {
+		name string
+		dark func(x, y int) bool
+		want rune
+	}{
+		{"vertical", func(x, y int) bool { return x >= 7*9+3 }, '|'},
+		{"falling", func(x, y int) bool { return y*7 > x*13 }, '\\'},
+	}
+	for _, tt := range tests {
+		for _, invert := range []bool{false, true} {
+			opts := DefaultOptions()
+			opts.Mode = ModeShape
+			opts.Metric = MetricNCC
+			opts.CellWidth, opts.CellHeight = 7, 13
+			opts.Invert = invert
+			res, err := Render(context.Background(), boundaryImage(140, 130, tt.dark), opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			edges := 0
+			for _, cell := range res.Canvas.Cells {
+				if cell.Kind != canvas.Edge {
+					continue
+				}
+				edges++
+				if cell.Rune != tt.want {
+					t.Errorf("%s, invert %v: edge cell got %q, want %q", tt.name, invert, cell.Rune, tt.want)
+				}
+			}
+			if edges == 0 {
+				t.Errorf("%s, invert %v: no edge cells", tt.name, invert)
+			}
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package font
+
+import (
+	"sync"
+
+	"golang.org/x/image/font/basicfont"
+)
+
+var (
+	basicOnce sync.Once
+	basic     *Font
+)
+
+// Basic returns built-in 7x13 font made from the X11 misc-fixed font,
+// it has printable ASCII letters
+func Basic() *Font {
+	basicOnce.Do(func() {
+		face := basicfont.Face7x13
+		height := face.Ascent + face.Descent
+		basic = &Font{
+			Width:    face.Advance,
+			Height:   height,
+			Glyphs:   make(map[rune]*Glyph),
+			Fallback: '�',
+		}
+		for _, rng := range face.Ranges {
+			for r := rng.Low; r < rng.High; r++ {
+				g := NewGlyph(basic.Width, basic.Height)
+				top := (int(r-rng.Low) + rng.Offset) * height
+				for y := 0; y < height; y++ {
+					for x := 0; x < face.Width; x++ {
+						_, _, _, a := face.Mask.At(face.Left+x, top+y).RGBA()
+						g.Set(x, y, a >= 0x8000)
+					}
+				}
+				basic.Glyphs[r] = g
+			}
+		}
+	})
+	return basic
+}
@@ -0,0 +1,135 @@
+package font
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+type bdfBox struct {
+	width, height, x, y int
+}
+
+// ParseBDF reads Glyph Bitmap Distribution Format font. The cell is the font
+// bounding box, glyphs are placed in it by their baseline
+func ParseBDF(r io.Reader) (*Font, error) {
+	scanner := bufio.NewScanner(r)
+	var fontBox bdfBox
+	hasBox := false
+	f := &Font{Glyphs: make(map[rune]*Glyph)}
+
+	var (
+		encoding  = -1
+		glyphBox  bdfBox
+		inBitmap  bool
+		bitmapRow int
+		glyph     *Glyph
+	)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+		if inBitmap {
+			if fields[0] == "ENDCHAR" {
+				if encoding >= 0 {
+					f.Glyphs[rune(encoding)] = glyph
+				}
+				inBitmap = false
+				continue
+			}
+			if err := setBDFRow(glyph, fields[0], bitmapRow, glyphBox, fontBox); err != nil {
+				return nil, err
+			}
+			bitmapRow++
+			continue
+		}
+
+		var err error
+		switch fields[0] {
+		case "FONTBOUNDINGBOX":
+			fontBox, err = parseBDFBox(fields)
+			if err == nil && (fontBox.width <= 0 || fontBox.height <= 0) {
+				err = fmt.Errorf("bdf: invalid FONTBOUNDINGBOX size %dx%d", fontBox.width, fontBox.height)
+			}
+			hasBox = true
+			f.Width, f.Height = fontBox.width, fontBox.height
+		case "STARTCHAR":
+			encoding = -1
+			glyphBox = fontBox
+		case "ENCODING":
+			if len(fields) > 1 {
+				encoding, err = strconv.Atoi(fields[1])
+			}
+		case "BBX":
+			glyphBox, err = parseBDFBox(fields)
+		case "BITMAP":
+			if !hasBox {
+				return nil, errors.New("bdf: BITMAP before FONTBOUNDINGBOX")
+			}
+			glyph = NewGlyph(f.Width, f.Height)
+			inBitmap = true
+			bitmapRow = 0
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(f.Glyphs) == 0 {
+		return nil, errors.New("bdf: font has no glyphs")
+	}
+	f.setFallback()
+	return f, nil
+}
+
+func parseBDFBox(fields []string) (bdfBox, error) {
+	if len(fields) < 5 {
+		return bdfBox{}, fmt.Errorf("bdf: invalid %s", fields[0])
+	}
+	var v [4]int
+	for i := range v {
+		n, err := strconv.Atoi(fields[i+1])
+		if err != nil {
+			return bdfBox{}, fmt.Errorf("bdf: invalid %s: %w", fields[0], err)
+		}
+		v[i] = n
+	}
+	return bdfBox{width: v[0], height: v[1], x: v[2], y: v[3]}, nil
+}
+
+// setBDFRow sets row of the glyph bitmap, rows go from the top of the glyph box
+func setBDFRow(g *Glyph, hex string, row int, glyphBox, fontBox bdfBox) error {
+	if len(hex)%2 != 0 {
+		hex += "0"
+	}
+	bits := make([]byte, len(hex)/2)
+	for i := range bits {
+		b, err := strconv.ParseUint(hex[2*i:2*i+2], 16, 8)
+		if err != nil {
+			return fmt.Errorf("bdf: invalid bitmap row %q", hex)
+		}
+		bits[i] = byte(b)
+	}
+	// baseline is fontBox.height+fontBox.y pixels from the top of the cell
+	y := fontBox.height + fontBox.y - (glyphBox.y + glyphBox.height) + row
+	if y < 0 || y >= g.Height {
+		return nil
+	}
+	width := len(bits) * 8
+	for bx := 0; bx < glyphBox.width && bx < width; bx++ {
+		x := glyphBox.x - fontBox.x + bx
+		if x < 0 || x >= g.Width {
+			continue
+		}
+		if bits[bx/8]&(0x80>>(bx%8)) != 0 {
+			g.Set(x, y, true)
+		}
+	}
+	return nil
+}
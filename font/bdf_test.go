@@ -0,0 +1,45 @@
+package font
+
+import (
+	"strings"
+	"testing"
+)
+
+const wideBDF = `STARTFONT 2.1
+FONTBOUNDINGBOX 72 2 0 0
+STARTCHAR A
+ENCODING 65
+BBX 72 2 0 0
+BITMAP
+800000000000000001
+FF
+ENDCHAR
+ENDFONT
+`
+
+func TestParseBDFWideGlyph(t *testing.T) {
+	f, err := ParseBDF(strings.NewReader(wideBDF))
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := f.Glyph('A')
+	for x := 0; x < 72; x++ {
+		want := x == 0 || x == 71
+		if g.At(x, 0) != want {
+			t.Errorf("row 0, x %d = %v, want %v", x, g.At(x, 0), want)
+		}
+		want = x < 8
+		if g.At(x, 1) != want {
+			t.Errorf("row 1, x %d = %v, want %v", x, g.At(x, 1), want)
+		}
+	}
+}
+
+func TestParseBDFInvalidBox(t *testing.T) {
+	for _, box := range []string{"-1 8 0 0", "8 0 0 0"} {
+		src := strings.Replace(wideBDF, "FONTBOUNDINGBOX 72 2 0 0", "FONTBOUNDINGBOX "+box, 1)
+		if _, err := ParseBDF(strings.NewReader(src)); err == nil {
+			t.Errorf("FONTBOUNDINGBOX %s: no error", box)
+		}
+	}
+}
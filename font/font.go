@@ -0,0 +1,64 @@
+package font
+
+// Glyph is a 1 bit bitmap of a letter, all glyphs of a font have the size of the font cell
+type Glyph struct {
+	Width  int
+	Height int
+	Bits   []bool
+}
+
+func NewGlyph(width, height int) *Glyph {
+	return &Glyph{Width: width, Height: height, Bits: make([]bool, width*height)}
+}
+
+func (g *Glyph) At(x, y int) bool {
+	return g.Bits[y*g.Width+x]
+}
+
+func (g *Glyph) Set(x, y int, v bool) {
+	g.Bits[y*g.Width+x] = v
+}
+
+// Coverage returns the share of set pixels, from 0 to 1
+func (g *Glyph) Coverage() float64 {
+	set := 0
+	for _, b := range g.Bits {
+		if b {
+			set++
+		}
+	}
+	return float64(set) / float64(len(g.Bits))
+}
+
+// Font is a monospace bitmap font
+type Font struct {
+	Width  int
+	Height int
+	Glyphs map[rune]*Glyph
+	// Fallback is drawn for the runes the font doesn't have
+	Fallback rune
+}
+
+// Glyph returns the glyph of the rune, block elements are drawn when the font
+// doesn't have them
+func (f *Font) Glyph(r rune) *Glyph {
+	if g, ok := f.Glyphs[r]; ok {
+		return g
+	}
+	if g := synthesize(r, f.Width, f.Height); g != nil {
+		return g
+	}
+	return f.Glyphs[f.Fallback]
+}
+
+// Has tells if the font itself has a glyph of the rune
+func (f *Font) Has(r rune) bool {
+	_, ok := f.Glyphs[r]
+	return ok
+}
+
+// CanDraw tells if Glyph returns a glyph of the rune and not the fallback,
+// that is the font has it or it is a block element drawn by the package
+func (f *Font) CanDraw(r rune) bool {
+	return f.Has(r) || synthesize(r, 1, 1) != nil
+}
@@ -0,0 +1,52 @@
+package font
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"os"
+)
+
+// Load reads a BDF or PSF font, the file can be gzipped
+func Load(filename string) (*Font, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	magic, _ := r.Peek(2)
+	if bytes.Equal(magic, []byte{0x1f, 0x8b}) {
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		r = bufio.NewReader(gz)
+	}
+
+	magic, _ = r.Peek(9)
+	switch {
+	case bytes.HasPrefix(magic, psf1Magic), bytes.HasPrefix(magic, psf2Magic):
+		return ParsePSF(r)
+	case bytes.HasPrefix(magic, []byte("STARTFONT")):
+		return ParseBDF(r)
+	}
+	return nil, errors.New("unknown font format of " + filename)
+}
+
+// setFallback chooses the glyph for runes the font doesn't have
+func (f *Font) setFallback() {
+	for _, r := range []rune{'�', '?', ' '} {
+		if f.Has(r) {
+			f.Fallback = r
+			return
+		}
+	}
+	for r := range f.Glyphs {
+		f.Fallback = r
+		return
+	}
+}
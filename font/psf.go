@@ -0,0 +1,179 @@
+package font
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"unicode/utf8"
+)
+
+var (
+	psf1Magic = []byte{0x36, 0x04}
+	psf2Magic = []byte{0x72, 0xb5, 0x4a, 0x86}
+)
+
+const (
+	psf1Mode512    = 0x01
+	psf1ModeHasTab = 0x02
+	psf1ModeSeq    = 0x04
+
+	psf2HasUnicodeTable = 0x01
+)
+
+// ParsePSF reads PC Screen Font version 1 or 2. Without unicode table
+// glyph number n is used for rune n
+func ParsePSF(r io.Reader) (*Font, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case bytes.HasPrefix(data, psf1Magic):
+		return parsePSF1(data)
+	case bytes.HasPrefix(data, psf2Magic):
+		return parsePSF2(data)
+	}
+	return nil, errors.New("not a psf font")
+}
+
+func parsePSF1(data []byte) (*Font, error) {
+	if len(data) < 4 {
+		return nil, errors.New("psf1: header is too short")
+	}
+	mode := data[2]
+	height := int(data[3])
+	if height == 0 {
+		return nil, errors.New("psf1: invalid glyph height 0")
+	}
+	count := 256
+	if mode&psf1Mode512 != 0 {
+		count = 512
+	}
+	glyphsEnd := 4 + count*height
+	if len(data) < glyphsEnd {
+		return nil, errors.New("psf1: file is too short")
+	}
+	glyphs := readPSFGlyphs(data[4:glyphsEnd], count, 8, height)
+
+	var table [][]rune
+	if mode&(psf1ModeHasTab|psf1ModeSeq) != 0 {
+		table = parsePSF1Table(data[glyphsEnd:], count)
+	}
+	return newPSFFont(glyphs, table, 8, height), nil
+}
+
+func parsePSF2(data []byte) (*Font, error) {
+	if len(data) < 32 {
+		return nil, errors.New("psf2: header is too short")
+	}
+	headerSize := int(binary.LittleEndian.Uint32(data[8:]))
+	flags := binary.LittleEndian.Uint32(data[12:])
+	count := int(binary.LittleEndian.Uint32(data[16:]))
+	charSize := int(binary.LittleEndian.Uint32(data[20:]))
+	height := int(binary.LittleEndian.Uint32(data[24:]))
+	width := int(binary.LittleEndian.Uint32(data[28:]))
+	// glyphs bigger than 65535 pixels aren't fonts, the limit also keeps the
+	// glyph size from overflowing
+	if width <= 0 || height <= 0 || width > 0xffff || height > 0xffff || charSize != (width+7)/8*height {
+		return nil, fmt.Errorf("psf2: invalid glyph size %dx%d", width, height)
+	}
+	if headerSize < 32 || headerSize > len(data) {
+		return nil, errors.New("psf2: invalid header size")
+	}
+	// header values are checked against the file size before multiplying so
+	// they can't overflow
+	if count == 0 || count > (len(data)-headerSize)/charSize {
+		return nil, errors.New("psf2: file is too short")
+	}
+	glyphsEnd := headerSize + count*charSize
+	glyphs := readPSFGlyphs(data[headerSize:glyphsEnd], count, width, height)
+
+	var table [][]rune
+	if flags&psf2HasUnicodeTable != 0 {
+		table = parsePSF2Table(data[glyphsEnd:], count)
+	}
+	return newPSFFont(glyphs, table, width, height), nil
+}
+
+func readPSFGlyphs(data []byte, count, width, height int) []*Glyph {
+	rowSize := (width + 7) / 8
+	glyphs := make([]*Glyph, count)
+	for i := range glyphs {
+		g := NewGlyph(width, height)
+		bitmap := data[i*rowSize*height:]
+		for y := 0; y < height; y++ {
+			for x := 0; x < width; x++ {
+				g.Set(x, y, bitmap[y*rowSize+x/8]&(0x80>>(x%8)) != 0)
+			}
+		}
+		glyphs[i] = g
+	}
+	return glyphs
+}
+
+// parsePSF1Table reads uint16 runes of every glyph ending with 0xffff,
+// sequences after 0xfffe are skipped
+func parsePSF1Table(data []byte, count int) [][]rune {
+	table := make([][]rune, count)
+	glyph := 0
+	sequence := false
+	for i := 0; i+1 < len(data) && glyph < count; i += 2 {
+		v := binary.LittleEndian.Uint16(data[i:])
+		switch {
+		case v == 0xffff:
+			glyph++
+			sequence = false
+		case v == 0xfffe:
+			sequence = true
+		case !sequence:
+			table[glyph] = append(table[glyph], rune(v))
+		}
+	}
+	return table
+}
+
+// parsePSF2Table reads utf-8 runes of every glyph ending with 0xff,
+// sequences after 0xfe are skipped
+func parsePSF2Table(data []byte, count int) [][]rune {
+	table := make([][]rune, count)
+	glyph := 0
+	sequence := false
+	for len(data) > 0 && glyph < count {
+		switch data[0] {
+		case 0xff:
+			glyph++
+			sequence = false
+			data = data[1:]
+			continue
+		case 0xfe:
+			sequence = true
+			data = data[1:]
+			continue
+		}
+		r, size := utf8.DecodeRune(data)
+		if !sequence && r != utf8.RuneError {
+			table[glyph] = append(table[glyph], r)
+		}
+		data = data[size:]
+	}
+	return table
+}
+
+func newPSFFont(glyphs []*Glyph, table [][]rune, width, height int) *Font {
+	f := &Font{Width: width, Height: height, Glyphs: make(map[rune]*Glyph)}
+	for i, g := range glyphs {
+		if table == nil {
+			f.Glyphs[rune(i)] = g
+			continue
+		}
+		for _, r := range table[i] {
+			if _, ok := f.Glyphs[r]; !ok {
+				f.Glyphs[r] = g
+			}
+		}
+	}
+	f.setFallback()
+	return f
+}
@@ -0,0 +1,62 @@
+package font
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+// TestParsePSF2KeepsBlockElements checks that glyphs of the font win over
+// block elements the package can draw itself
+func TestParsePSF2KeepsBlockElements(t *testing.T) {
+	const width, height = 8, 4
+	data := make([]byte, 32)
+	copy(data, psf2Magic)
+	binary.LittleEndian.PutUint32(data[8:], 32)
+	binary.LittleEndian.PutUint32(data[12:], psf2HasUnicodeTable)
+	binary.LittleEndian.PutUint32(data[16:], 1)
+	binary.LittleEndian.PutUint32(data[20:], height)
+	binary.LittleEndian.PutUint32(data[24:], height)
+	binary.LittleEndian.PutUint32(data[28:], width)
+	// only the top row of █ is set
+	data = append(data, 0xff, 0, 0, 0)
+	data = append(data, []byte("█")...)
+	data = append(data, 0xff)
+
+	f, err := ParsePSF(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !f.Has('█') {
+		t.Fatal("font has no █")
+	}
+	if coverage := f.Glyph('█').Coverage(); coverage != 0.25 {
+		t.Errorf("coverage of █ = %v, want 0.25", coverage)
+	}
+}
+
+// psf2Header returns a PSF2 header without unicode table
+func psf2Header(count, charSize, height, width uint32) []byte {
+	data := make([]byte, 32)
+	copy(data, psf2Magic)
+	binary.LittleEndian.PutUint32(data[8:], 32)
+	binary.LittleEndian.PutUint32(data[16:], count)
+	binary.LittleEndian.PutUint32(data[20:], charSize)
+	binary.LittleEndian.PutUint32(data[24:], height)
+	binary.LittleEndian.PutUint32(data[28:], width)
+	return data
+}
+
+func TestParsePSFMalformedHeader(t *testing.T) {
+	tests := map[string][]byte{
+		"overflowing glyph count": append(psf2Header(0xffffffff, 0xffff0000, 0xffff0000, 8), make([]byte, 64)...),
+		"too many glyphs":         append(psf2Header(1000, 4, 4, 8), make([]byte, 64)...),
+		"no glyphs":               psf2Header(0, 4, 4, 8),
+		"psf1 height 0":           append([]byte{0x36, 0x04, 0, 0}, make([]byte, 256)...),
+	}
+	for name, data := range tests {
+		if _, err := ParsePSF(bytes.NewReader(data)); err == nil {
+			t.Errorf("%s: no error", name)
+		}
+	}
+}
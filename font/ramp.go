@@ -0,0 +1,76 @@
+package font
+
+import (
+	"math"
+	"slices"
+)
+
+// DensityRamp orders letters of the charset by ink coverage of their glyphs,
+// from the lightest to the densest. Letters the font doesn't have are skipped.
+// When levels > 0 it picks up to levels letters with evenly spaced coverage
+func DensityRamp(f *Font, charset []rune, levels int) []rune {
+	type letter struct {
+		r        rune
+		coverage float64
+	}
+	var letters []letter
+	seen := make(map[rune]bool)
+	for _, r := range charset {
+		if seen[r] || !f.CanDraw(r) {
+			continue
+		}
+		seen[r] = true
+		letters = append(letters, letter{r, f.Glyph(r).Coverage()})
+	}
+	slices.SortStableFunc(letters, func(a, b letter) int {
+		if a.coverage < b.coverage {
+			return -1
+		}
+		if a.coverage > b.coverage {
+			return 1
+		}
+		return 0
+	})
+	if len(letters) == 0 {
+		return nil
+	}
+
+	if levels <= 0 || levels >= len(letters) {
+		ramp := make([]rune, len(letters))
+		for i, l := range letters {
+			ramp[i] = l.r
+		}
+		return ramp
+	}
+
+	lightest := letters[0].coverage
+	densest := letters[len(letters)-1].coverage
+	ramp := make([]rune, 0, levels)
+	next := 0
+	for i := 0; i < levels; i++ {
+		target := lightest
+		if levels > 1 {
+			target += (densest - lightest) * float64(i) / float64(levels-1)
+		}
+		// letters can't be taken twice, leave enough letters for the next levels
+		last := len(letters) - (levels - i)
+		best := next
+		for j := next; j <= last; j++ {
+			if math.Abs(letters[j].coverage-target) < math.Abs(letters[best].coverage-target) {
+				best = j
+			}
+		}
+		ramp = append(ramp, letters[best].r)
+		next = best + 1
+	}
+	return ramp
+}
+
+// PrintableASCII returns letters from space to tilde
+func PrintableASCII() []rune {
+	var charset []rune
+	for r := ' '; r <= '~'; r++ {
+		charset = append(charset, r)
+	}
+	return charset
+}
@@ -0,0 +1,197 @@
+package font
+
+// synthesize draws glyphs made of rectangles, so block elements work with
+// any font. It returns nil for other runes
+func synthesize(r rune, width, height int) *Glyph {
+	if r >= 0x2800 && r <= 0x28ff {
+		return braille(r, width, height)
+	}
+	if r >= 0x1fb00 && r <= 0x1fb3b {
+		return sextant(r, width, height)
+	}
+	if r >= 0x2500 && r <= 0x257f {
+		return boxDrawing(r, width, height)
+	}
+	if r < 0x2580 || r > 0x259f {
+		return nil
+	}
+	g := NewGlyph(width, height)
+	switch {
+	case r == 0x2580: // ▀
+		fillRect(g, 0, 0, 1, 0.5)
+	case r >= 0x2581 && r <= 0x2588: // ▁ to █, lower eighths
+		fillRect(g, 0, 1-float64(r-0x2580)/8, 1, 1)
+	case r >= 0x2589 && r <= 0x258f: // ▉ to ▏, left eighths
+		fillRect(g, 0, 0, float64(0x2590-r)/8, 1)
+	case r == 0x2590: // ▐
+		fillRect(g, 0.5, 0, 1, 1)
+	case r >= 0x2591 && r <= 0x2593: // ░ ▒ ▓
+		shade(g, int(r-0x2590))
+	case r == 0x2594: // ▔
+		fillRect(g, 0, 0, 1, 1.0/8)
+	case r == 0x2595: // ▕
+		fillRect(g, 7.0/8, 0, 1, 1)
+	default:
+		fillMask(g, 2, 2, quadrants[r-0x2596])
+	}
+	return g
+}
+
+// quadrants of ▖ to ▟, bits are upper left, upper right, lower left, lower right
+var quadrants = []uint{
+	0b0010, 0b0001, 0b1000, 0b1011, 0b1001, 0b1110, 0b1101, 0b0100, 0b0110, 0b0111,
+}
+
+// boxArms are the weights of the lines from the center of box-drawing letters
+// up, right, down and left, 1 is light and 2 is heavy
+var boxArms = map[rune][4]int{
+	'─': {0, 1, 0, 1},
+	'━': {0, 2, 0, 2},
+	'│': {1, 0, 1, 0},
+	'┃': {2, 0, 2, 0},
+	'┌': {0, 1, 1, 0},
+	'┐': {0, 0, 1, 1},
+	'└': {1, 1, 0, 0},
+	'┘': {1, 0, 0, 1},
+	'├': {1, 1, 1, 0},
+	'┤': {1, 0, 1, 1},
+	'┬': {0, 1, 1, 1},
+	'┴': {1, 1, 0, 1},
+	'┼': {1, 1, 1, 1},
+}
+
+// boxDrawing draws lines of box-drawing letters through the middle of the cell
+func boxDrawing(r rune, width, height int) *Glyph {
+	g := NewGlyph(width, height)
+	switch r {
+	case '╱', '╲', '╳':
+		for y := 0; y < height; y++ {
+			// y is flipped for ╲ so both diagonals go corner to corner
+			t := (float64(y) + 0.5) / float64(height)
+			if r == '╲' {
+				t = 1 - t
+			}
+			x := int(float64(width) * (1 - t))
+			g.Set(min(x, width-1), y, true)
+			if r == '╳' {
+				g.Set(min(int(float64(width)*t), width-1), y, true)
+			}
+		}
+		return g
+	}
+	arms, ok := boxArms[r]
+	if !ok {
+		return nil
+	}
+	centerX, centerY := width/2, height/2
+	for i, weight := range arms {
+		for w := 0; w < weight; w++ {
+			// heavy lines are two pixels wide
+			lineX, lineY := max(centerX-w, 0), max(centerY-w, 0)
+			switch i {
+			case 0:
+				for y := 0; y <= centerY; y++ {
+					g.Set(lineX, y, true)
+				}
+			case 1:
+				for x := centerX; x < width; x++ {
+					g.Set(x, lineY, true)
+				}
+			case 2:
+				for y := centerY; y < height; y++ {
+					g.Set(lineX, y, true)
+				}
+			case 3:
+				for x := 0; x <= centerX; x++ {
+					g.Set(x, lineY, true)
+				}
+			}
+		}
+	}
+	return g
+}
+
+// brailleBits are the bits of the dots, indexed by row and column
+var brailleBits = [4][2]rune{
+	{0x01, 0x08},
+	{0x02, 0x10},
+	{0x04, 0x20},
+	{0x40, 0x80},
+}
+
+// braille draws the dots as squares in a 2x4 grid
+func braille(r rune, width, height int) *Glyph {
+	g := NewGlyph(width, height)
+	for row := 0; row < 4; row++ {
+		for col := 0; col < 2; col++ {
+			if (r-0x2800)&brailleBits[row][col] == 0 {
+				continue
+			}
+			x0, y0 := (float64(col)+0.25)/2, (float64(row)+0.25)/4
+			fillRect(g, x0, y0, x0+0.25, y0+0.125)
+		}
+	}
+	return g
+}
+
+// sextant draws the parts of a 2x3 grid, the bit 1 of the mask is the upper
+// left part, then row by row. Masks of the half blocks are skipped by Unicode
+func sextant(r rune, width, height int) *Glyph {
+	mask := int(r-0x1fb00) + 1
+	if mask >= 0b010101 {
+		mask++
+	}
+	if mask >= 0b101010 {
+		mask++
+	}
+	g := NewGlyph(width, height)
+	for part := 0; part < 6; part++ {
+		if mask&(1<<part) == 0 {
+			continue
+		}
+		col, row := part%2, part/2
+		fillRect(g, float64(col)/2, float64(row)/3, float64(col+1)/2, float64(row+1)/3)
+	}
+	return g
+}
+
+// fillRect sets pixels inside the rectangle given in parts of the glyph size
+func fillRect(g *Glyph, x0, y0, x1, y1 float64) {
+	for y := int(y0*float64(g.Height) + 0.5); y < int(y1*float64(g.Height)+0.5); y++ {
+		for x := int(x0*float64(g.Width) + 0.5); x < int(x1*float64(g.Width)+0.5); x++ {
+			g.Set(x, y, true)
+		}
+	}
+}
+
+// fillMask splits the glyph into columns x rows parts and fills the parts whose
+// bits are set, the highest bit is the upper left part, then row by row
+func fillMask(g *Glyph, columns, rows int, mask uint) {
+	parts := columns * rows
+	for i := 0; i < parts; i++ {
+		if mask&(1<<(parts-1-i)) == 0 {
+			continue
+		}
+		col, row := i%columns, i/columns
+		fillRect(g, float64(col)/float64(columns), float64(row)/float64(rows),
+			float64(col+1)/float64(columns), float64(row+1)/float64(rows))
+	}
+}
+
+// shade sets level quarters of the pixels in a regular pattern
+func shade(g *Glyph, level int) {
+	for y := 0; y < g.Height; y++ {
+		for x := 0; x < g.Width; x++ {
+			var set bool
+			switch level {
+			case 1:
+				set = x%2 == 0 && y%2 == 0
+			case 2:
+				set = (x+y)%2 == 0
+			default:
+				set = !(x%2 == 0 && y%2 == 0)
+			}
+			g.Set(x, y, set)
+		}
+	}
+}
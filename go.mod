@@ -4,4 +4,4 @@ go 1.22.2
 
 require github.com/disintegration/imaging v1.6.2
 
-require golang.org/x/image v0.19.0 // indirect
+require golang.org/x/image v0.19.0
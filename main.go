@@ -2,36 +2,234 @@ package main
 
 import (
 	"ascii/effects"
+	"ascii/font"
+	"ascii/output"
+	"ascii/pixel"
 	"ascii/utils"
+	"context"
+	"flag"
+	"fmt"
 	"log"
 	"os"
+	"path/filepath"
 	"strconv"
-)
+	"strings"
 
-var asciiTexture = []string{" ", ".", ":", "-", "=", "+", "*", "#", "%", "@"}
+	"github.com/disintegration/imaging"
+)
 
 func main() {
+	opts := effects.DefaultOptions()
+	mode := flag.String("mode", "ramp", "ramp draws edges with | _ / \\ and the rest by luminance, shape chooses letters whose glyphs look like the cell, halfblock draws ▀ with two colored pixels per cell, braille draws 2x4 dots per cell, quadrant and sextant draw 2x2 and 2x3 block mosaics with two colors per cell")
+	metric := flag.String("match", "ssim", "how shape mode compares cells with glyphs: ssim or ncc")
+	charset := flag.String("charset", "", "letters shape mode chooses from, printable ASCII by default")
+	flag.Float64Var(&opts.Sigma, "sigma", opts.Sigma, "sigma of the smaller gaussian in the difference of gaussians")
+	flag.Float64Var(&opts.K, "k", opts.K, "multiplier of sigma for the bigger gaussian")
+	flag.Float64Var(&opts.KernelRadius, "kernel-radius", opts.KernelRadius, "radius of gaussian kernels in sigmas")
+	flag.IntVar(&opts.DoGThreshold, "dog-threshold", opts.DoGThreshold, "threshold (0-255) of the difference of gaussians")
+	flag.Float64Var(&opts.SobelThreshold, "sobel-threshold", opts.SobelThreshold, "minimal gradient magnitude of the sobel operator")
+	flag.IntVar(&opts.EdgeThreshold, "edge-threshold", opts.EdgeThreshold, "minimal amount of edge pixels in a cell to draw an edge letter")
+	edges := flag.String("edges", "ascii", "letters of edges: ascii for | _ / \\ or unicode for box-drawing ─ │ ╱ ╲")
+	flag.Float64Var(&opts.HeavyThreshold, "heavy-threshold", opts.HeavyThreshold, "mean sobel magnitude of the image over the edge pixels of a cell for heavy ━ ┃ edges of the unicode set, a step from black to white is 262140")
+	flag.BoolVar(&opts.Junctions, "junctions", opts.Junctions, "draw corners and crossings where edges meet: + and x, or ┌ ┐ └ ┘ ├ ┤ ┬ ┴ ┼ ╳ with --edges unicode")
+	borderMode := flag.String("border", "clamp", "what filters see outside of the image: clamp, reflect, wrap or constant")
+	borderColor := flag.String("border-color", "#000000", "color outside of the image for the constant border")
+	flag.IntVar(&opts.CellWidth, "cell", opts.CellWidth, "width of a cell in pixels, one cell becomes one letter")
+	flag.IntVar(&opts.CellHeight, "cell-height", opts.CellHeight, "height of a cell in pixels, 0 means the same as the width")
+	flag.Float64Var(&opts.CharAspect, "char-aspect", opts.CharAspect, "height/width of the font letters, sets the cell height from the width (2 for most terminals)")
+	fontFile := flag.String("font", "", "BDF or PSF bitmap font for shape mode, --sort-ramp and png/jpg output, sets --char-aspect from its cell")
+	flag.IntVar(&opts.Columns, "width", opts.Columns, "width of the art in letters, the image is resized to it")
+	flag.IntVar(&opts.Rows, "height", opts.Rows, "height of the art in letters, the image is resized to it")
+	fitTerminal := flag.Bool("fit-terminal", false, "fit the art into the terminal")
+	sampler := flag.String("sampler", "mean", "how a cell gets luminance and color from its pixels: mean, median, center, max or gaussian")
+	luminance := flag.String("luminance", "rec601", "luminance model: rec601, rec709 (linear light), lab, hsl or hsv")
+	ramp := flag.String("ramp", "short", "letters from the lightest to the densest or a built-in ramp: "+strings.Join(effects.RampNames(), ", "))
+	rampFile := flag.String("ramp-file", "", "file with the letters of the ramp")
+	sortRamp := flag.Bool("sort-ramp", false, "order the letters of the ramp by their density in --font or the built-in font")
+	rampLevels := flag.Int("ramp-levels", 0, "with --sort-ramp pick this many letters with evenly spaced density, 0 keeps all")
+	flag.BoolVar(&opts.Invert, "invert", opts.Invert, "invert the ramp for light backgrounds")
+	flag.BoolVar(&opts.Dither, "dither", opts.Dither, "braille mode sets dots by ordered dithering of the image instead of the difference of gaussians")
+	flag.BoolVar(&opts.AddColors, "color", opts.AddColors, "get ASCII in color")
+	flag.StringVar(&opts.Output, "out", opts.Output, "output file, - for stdout")
+	format := flag.String("format", "", "output format: html, ansi, txt, svg, png or jpg, by default it is chosen by the output file extension")
+	colors := flag.String("colors", "truecolor", "colors of the ansi format: truecolor, 256, 16 or mono")
+	background := flag.String("background", "#000000", "background color of the svg, png and jpg formats")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [flags] <image_file>\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
 
-	if len(os.Args) <= 1 {
+	if flag.NArg() < 1 {
 		print("Error! Please enter image filename as an argument.")
 		return
 	}
 
-	filename := os.Args[1]
+	filename := flag.Arg(0)
 	im, err := utils.OpenFile(filename)
 	if err != nil {
 		log.Fatal(err)
 	}
-	addColors := false
-	if len(os.Args) > 2 {
-		addColors, err = strconv.ParseBool(os.Args[2])
+	// old way of passing colors: <image_file> true/false
+	if flag.NArg() > 1 {
+		opts.AddColors, err = strconv.ParseBool(flag.Arg(1))
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	setFlags := make(map[string]bool)
+	flag.Visit(func(f *flag.Flag) {
+		setFlags[f.Name] = true
+	})
+
+	glyphs := font.Basic()
+	if *fontFile != "" {
+		glyphs, err = font.Load(*fontFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if !setFlags["char-aspect"] && !setFlags["cell-height"] {
+			opts.CharAspect = float64(glyphs.Height) / float64(glyphs.Width)
+		}
+	}
+
+	if *fitTerminal {
+		cols, rows, err := utils.TerminalSize()
+		if err != nil {
+			log.Fatal(err)
+		}
+		// leave a line for the prompt
+		opts.Columns, opts.Rows = cols, rows-1
+	}
+
+	opts.Ramp = effects.ParseRamp(*ramp)
+	if *rampFile != "" {
+		opts.Ramp, err = effects.ReadRampFile(*rampFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	if *sortRamp {
+		opts.Ramp = font.DensityRamp(glyphs, opts.Ramp, *rampLevels)
+		if len(opts.Ramp) == 0 {
+			log.Fatal("none of the ramp letters are in the font")
+		}
+	}
+
+	opts.Mode, err = effects.ParseMode(*mode)
+	if err != nil {
+		log.Fatal(err)
+	}
+	opts.Metric, err = effects.ParseMetric(*metric)
+	if err != nil {
+		log.Fatal(err)
+	}
+	opts.Edges, err = effects.ParseEdgeSet(*edges)
+	if err != nil {
+		log.Fatal(err)
+	}
+	opts.Font = glyphs
+	opts.Charset = []rune(*charset)
+
+	opts.Luminance, err = pixel.ParseLuminanceModel(*luminance)
+	if err != nil {
+		log.Fatal(err)
+	}
+	opts.Sampler, err = effects.ParseSampler(*sampler)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	opts.Border.Mode, err = effects.ParseBorderMode(*borderMode)
+	if err != nil {
+		log.Fatal(err)
+	}
+	opts.Border.Color, err = pixel.ParseHexColor(*borderColor)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	colorMode, err := output.ParseColorMode(*colors)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	svgOpts := output.DefaultSVGOptions()
+	svgOpts.Background, err = pixel.ParseHexColor(*background)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	rasterOpts := output.DefaultRasterOptions()
+	rasterOpts.Background = svgOpts.Background
+	rasterOpts.Font = glyphs
+
+	if *format == "" {
+		*format = detectFormat(opts.Output)
+	}
+	// check the format before the output file is truncated
+	switch *format {
+	case "html", "ansi", "txt", "svg", "png", "jpg":
+	default:
+		log.Fatalf("unknown format %q", *format)
+	}
+	// ansi is for previewing in the terminal
+	if *format == "ansi" && !setFlags["out"] {
+		opts.Output = "-"
+	}
+
+	res, err := effects.Render(context.Background(), im, opts)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	w := os.Stdout
+	if opts.Output != "-" {
+		w, err = os.Create(opts.Output)
 		if err != nil {
 			log.Fatal(err)
 		}
 	}
 
-	err = effects.GenerateAsciiFiles(im, asciiTexture, addColors)
+	switch *format {
+	case "html":
+		err = output.WriteHTML(w, res.Canvas)
+	case "ansi":
+		err = output.WriteANSI(w, res.Canvas, colorMode)
+	case "txt":
+		err = output.WriteText(w, res.Canvas)
+	case "svg":
+		err = output.WriteSVG(w, res.Canvas, svgOpts)
+	case "png":
+		err = imaging.Encode(w, output.Raster(res.Canvas, rasterOpts), imaging.PNG)
+	case "jpg":
+		err = imaging.Encode(w, output.Raster(res.Canvas, rasterOpts), imaging.JPEG)
+	}
 	if err != nil {
 		log.Fatal(err)
 	}
+	if err = w.Close(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func detectFormat(filename string) string {
+	if filename == "-" {
+		return "txt"
+	}
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".txt":
+		return "txt"
+	case ".ans":
+		return "ansi"
+	case ".svg":
+		return "svg"
+	case ".png":
+		return "png"
+	case ".jpg", ".jpeg":
+		return "jpg"
+	}
+	return "html"
 }
@@ -0,0 +1,74 @@
+package output
+
+import (
+	"ascii/canvas"
+	"bufio"
+	"fmt"
+	"image/color"
+	"io"
+)
+
+const ansiReset = "\x1b[0m"
+
+// WriteANSI writes art with foreground and background colors in the given
+// mode, the color escape is written only when it differs from the previous cell
+func WriteANSI(w io.Writer, art *canvas.Canvas, mode ColorMode) error {
+	var p *palette
+	switch mode {
+	case Color256:
+		p = newPalette(xterm256(), 16)
+	case Color16:
+		p = newPalette(basic16, 0)
+	}
+
+	bw := bufio.NewWriter(w)
+	for y := 0; y < art.Height; y++ {
+		currentFG, currentBG := "", ""
+		for _, cell := range art.Row(y) {
+			fg := ansiColor(cell.FG, mode, p, false)
+			bg := ansiColor(cell.BG, mode, p, true)
+			// the only way to unset a color is to reset both
+			if (fg == "" && currentFG != "") || (bg == "" && currentBG != "") {
+				bw.WriteString(ansiReset)
+				currentFG, currentBG = "", ""
+			}
+			if fg != currentFG {
+				bw.WriteString(fg)
+				currentFG = fg
+			}
+			if bg != currentBG {
+				bw.WriteString(bg)
+				currentBG = bg
+			}
+			bw.WriteRune(cell.Rune)
+		}
+		if currentFG != "" || currentBG != "" {
+			bw.WriteString(ansiReset)
+		}
+		bw.WriteByte('\n')
+	}
+	return bw.Flush()
+}
+
+func ansiColor(c color.NRGBA, mode ColorMode, p *palette, background bool) string {
+	if c.A == 0 || mode == Mono {
+		return ""
+	}
+	// 38 and 48 for extended colors, 30-37 and 90-97 for 16 colors foreground,
+	// background codes are 10 more
+	base := 0
+	if background {
+		base = 10
+	}
+	switch mode {
+	case Color256:
+		return fmt.Sprintf("\x1b[%d;5;%dm", 38+base, p.nearest(c))
+	case Color16:
+		i := p.nearest(c)
+		if i < 8 {
+			return fmt.Sprintf("\x1b[%dm", 30+base+i)
+		}
+		return fmt.Sprintf("\x1b[%dm", 90+base+i-8)
+	}
+	return fmt.Sprintf("\x1b[%d;2;%d;%d;%dm", 38+base, c.R, c.G, c.B)
+}
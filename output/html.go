@@ -0,0 +1,77 @@
+package output
+
+import (
+	"ascii/canvas"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+)
+
+func WriteHTML(w io.Writer, art *canvas.Canvas) error {
+	var result strings.Builder
+	for y := 0; y < art.Height; y++ {
+		for _, cell := range art.Row(y) {
+			letter := html.EscapeString(string(cell.Rune))
+			if cell.FG.A == 0 && cell.BG.A == 0 {
+				result.WriteString(letter)
+				continue
+			}
+			result.WriteString("<span style='")
+			if cell.FG.A != 0 {
+				fmt.Fprintf(&result, "color: rgb(%d, %d, %d);", cell.FG.R, cell.FG.G, cell.FG.B)
+			}
+			if cell.BG.A != 0 {
+				if cell.FG.A != 0 {
+					result.WriteString(" ")
+				}
+				fmt.Fprintf(&result, "background-color: rgb(%d, %d, %d);", cell.BG.R, cell.BG.G, cell.BG.B)
+			}
+			result.WriteString("'>" + letter + "</span>")
+		}
+		result.WriteString("\n")
+	}
+	_, err := io.WriteString(w, generateHTML(result.String()))
+	return err
+}
+
+func generateHTML(asciiArt string) string {
+	return `
+	<!DOCTYPE html>
+	<html lang="en">
+	<head>
+		<meta charset="UTF-8">
+		<meta name="viewport" content="width=device-width, initial-scale=0.4">
+		<title>RaiMei</title>
+	</head>
+	<body >
+		<pre>` + asciiArt + `</pre>
+	</body>
+	<style>
+		body{
+			background: black;
+			margin: 0px;
+			padding: 0px;
+			display: flex;
+			justify-content: center;
+			height: 100vh;
+  			width: 100vw;
+		}
+
+		pre {
+			background: inherit;
+			color: white;
+			font-family: monospace;
+			letter-spacing: 0.5em;
+			font-size: 0.4em;
+
+		}
+
+		pre .height-scaling {
+			letter-spacing: 0.2vh;
+			font-size: 0.4vh;
+		}
+
+	</style>
+	</html>`
+}
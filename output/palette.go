@@ -0,0 +1,96 @@
+package output
+
+import (
+	"ascii/pixel"
+	"fmt"
+	"image/color"
+)
+
+type ColorMode int
+
+const (
+	TrueColor ColorMode = iota
+	Color256
+	Color16
+	Mono
+)
+
+func ParseColorMode(s string) (ColorMode, error) {
+	switch s {
+	case "truecolor", "24bit":
+		return TrueColor, nil
+	case "256":
+		return Color256, nil
+	case "16":
+		return Color16, nil
+	case "mono":
+		return Mono, nil
+	}
+	return TrueColor, fmt.Errorf("unknown color mode %q", s)
+}
+
+type labColor struct {
+	l, a, b float64
+}
+
+type palette struct {
+	// index of the first color in terminal numbering
+	offset int
+	colors []labColor
+	cache  map[color.NRGBA]int
+}
+
+// default xterm colors, terminals with themes can show them differently
+var basic16 = []color.NRGBA{
+	{0, 0, 0, 255}, {205, 0, 0, 255}, {0, 205, 0, 255}, {205, 205, 0, 255},
+	{0, 0, 238, 255}, {205, 0, 205, 255}, {0, 205, 205, 255}, {229, 229, 229, 255},
+	{127, 127, 127, 255}, {255, 0, 0, 255}, {0, 255, 0, 255}, {255, 255, 0, 255},
+	{92, 92, 255, 255}, {255, 0, 255, 255}, {0, 255, 255, 255}, {255, 255, 255, 255},
+}
+
+// xterm256 returns colors 16-255, the first 16 depend on the terminal theme
+func xterm256() []color.NRGBA {
+	levels := []uint8{0, 95, 135, 175, 215, 255}
+	colors := make([]color.NRGBA, 0, 240)
+	for r := 0; r < 6; r++ {
+		for g := 0; g < 6; g++ {
+			for b := 0; b < 6; b++ {
+				colors = append(colors, color.NRGBA{R: levels[r], G: levels[g], B: levels[b], A: 255})
+			}
+		}
+	}
+	for i := 0; i < 24; i++ {
+		v := uint8(8 + i*10)
+		colors = append(colors, color.NRGBA{R: v, G: v, B: v, A: 255})
+	}
+	return colors
+}
+
+func newPalette(colors []color.NRGBA, offset int) *palette {
+	p := &palette{offset: offset, cache: make(map[color.NRGBA]int)}
+	for _, c := range colors {
+		l, a, b := pixel.RGBToLab(c)
+		p.colors = append(p.colors, labColor{l, a, b})
+	}
+	return p
+}
+
+// nearest returns terminal index of the palette color closest to c in CIELAB
+func (p *palette) nearest(c color.NRGBA) int {
+	if i, ok := p.cache[c]; ok {
+		return i
+	}
+	l, a, b := pixel.RGBToLab(c)
+	best := 0
+	bestDistance := -1.0
+	for i, pc := range p.colors {
+		dl, da, db := l-pc.l, a-pc.a, b-pc.b
+		distance := dl*dl + da*da + db*db
+		if bestDistance < 0 || distance < bestDistance {
+			best = i
+			bestDistance = distance
+		}
+	}
+	p.cache[c] = best + p.offset
+	return best + p.offset
+}
@@ -0,0 +1,58 @@
+package output
+
+import (
+	"ascii/canvas"
+	"ascii/font"
+	"image"
+	"image/color"
+	"sync"
+)
+
+type RasterOptions struct {
+	Font       *font.Font
+	Background color.NRGBA
+	// color of the cells without a color
+	Foreground color.NRGBA
+}
+
+func DefaultRasterOptions() RasterOptions {
+	return RasterOptions{
+		Font:       font.Basic(),
+		Background: color.NRGBA{A: 255},
+		Foreground: color.NRGBA{R: 255, G: 255, B: 255, A: 255},
+	}
+}
+
+// Raster draws art into an image, every cell takes the size of the font cell
+func Raster(art *canvas.Canvas, opts RasterOptions) *image.NRGBA {
+	f := opts.Font
+	im := image.NewNRGBA(image.Rect(0, 0, art.Width*f.Width, art.Height*f.Height))
+	w := new(sync.WaitGroup)
+	for y := 0; y < art.Height; y++ {
+		w.Add(1)
+		go func(y int) {
+			defer w.Done()
+			for x, cell := range art.Row(y) {
+				fg, bg := cell.FG, cell.BG
+				if fg.A == 0 {
+					fg = opts.Foreground
+				}
+				if bg.A == 0 {
+					bg = opts.Background
+				}
+				glyph := f.Glyph(cell.Rune)
+				for gy := 0; gy < f.Height; gy++ {
+					for gx := 0; gx < f.Width; gx++ {
+						clr := bg
+						if glyph != nil && glyph.At(gx, gy) {
+							clr = fg
+						}
+						im.SetNRGBA(x*f.Width+gx, y*f.Height+gy, clr)
+					}
+				}
+			}
+		}(y)
+	}
+	w.Wait()
+	return im
+}
@@ -0,0 +1,122 @@
+package output
+
+import (
+	"ascii/canvas"
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"image/color"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type SVGOptions struct {
+	// distance between neighbour letters and between rows
+	CellWidth  float64
+	CellHeight float64
+	FontSize   float64
+	Background color.NRGBA
+	// color of the cells without a color
+	Foreground color.NRGBA
+}
+
+func DefaultSVGOptions() SVGOptions {
+	return SVGOptions{
+		CellWidth:  10,
+		CellHeight: 10,
+		FontSize:   10,
+		Background: color.NRGBA{A: 255},
+		Foreground: color.NRGBA{R: 255, G: 255, B: 255, A: 255},
+	}
+}
+
+// WriteSVG writes every row as a <text> element, letters with the same color
+// are grouped in a <tspan>, every letter has its own x so the pitch doesn't
+// depend on the font
+func WriteSVG(w io.Writer, art *canvas.Canvas, opts SVGOptions) error {
+	bw := bufio.NewWriter(w)
+	width := float64(art.Width) * opts.CellWidth
+	height := float64(art.Height) * opts.CellHeight
+	fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" width="%s" height="%s" viewBox="0 0 %s %s">`+"\n",
+		formatFloat(width), formatFloat(height), formatFloat(width), formatFloat(height))
+	if opts.Background.A != 0 {
+		fmt.Fprintf(bw, `<rect width="100%%" height="100%%" fill="%s"/>`+"\n", hexColor(opts.Background))
+	}
+	writeSVGBackgrounds(bw, art, opts)
+	fmt.Fprintf(bw, `<g font-family="monospace" font-size="%s" xml:space="preserve">`+"\n", formatFloat(opts.FontSize))
+
+	for y := 0; y < art.Height; y++ {
+		row := art.Row(y)
+		// baseline is a bit above the bottom of the cell to leave place for descenders
+		baseline := float64(y+1)*opts.CellHeight - opts.FontSize*0.2
+		started := false
+		for x := 0; x < len(row); {
+			if unicode.IsSpace(row[x].Rune) {
+				x++
+				continue
+			}
+			fill := row[x].FG
+			if fill.A == 0 {
+				fill = opts.Foreground
+			}
+			end := x
+			var positions []string
+			var text strings.Builder
+			for ; end < len(row) && !unicode.IsSpace(row[end].Rune) && (row[end].FG == row[x].FG); end++ {
+				positions = append(positions, formatFloat(float64(end)*opts.CellWidth))
+				text.WriteRune(row[end].Rune)
+			}
+			if !started {
+				fmt.Fprintf(bw, `<text y="%s">`, formatFloat(baseline))
+				started = true
+			}
+			fmt.Fprintf(bw, `<tspan x="%s" fill="%s">`, strings.Join(positions, " "), hexColor(fill))
+			xml.EscapeText(bw, []byte(text.String()))
+			bw.WriteString("</tspan>")
+			x = end
+		}
+		if started {
+			bw.WriteString("</text>\n")
+		}
+	}
+	bw.WriteString("</g>\n</svg>\n")
+	return bw.Flush()
+}
+
+// writeSVGBackgrounds draws a rect for every run of cells with the same background
+func writeSVGBackgrounds(bw *bufio.Writer, art *canvas.Canvas, opts SVGOptions) {
+	started := false
+	for y := 0; y < art.Height; y++ {
+		row := art.Row(y)
+		for x := 0; x < len(row); {
+			bg := row[x].BG
+			end := x + 1
+			for end < len(row) && row[end].BG == bg {
+				end++
+			}
+			if bg.A != 0 {
+				if !started {
+					bw.WriteString(`<g shape-rendering="crispEdges">` + "\n")
+					started = true
+				}
+				fmt.Fprintf(bw, `<rect x="%s" y="%s" width="%s" height="%s" fill="%s"/>`+"\n",
+					formatFloat(float64(x)*opts.CellWidth), formatFloat(float64(y)*opts.CellHeight),
+					formatFloat(float64(end-x)*opts.CellWidth), formatFloat(opts.CellHeight), hexColor(bg))
+			}
+			x = end
+		}
+	}
+	if started {
+		bw.WriteString("</g>\n")
+	}
+}
+
+func hexColor(c color.NRGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
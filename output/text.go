@@ -0,0 +1,20 @@
+package output
+
+import (
+	"ascii/canvas"
+	"bufio"
+	"io"
+)
+
+func WriteText(w io.Writer, art *canvas.Canvas) error {
+	bw := bufio.NewWriter(w)
+	for y := 0; y < art.Height; y++ {
+		for _, cell := range art.Row(y) {
+			bw.WriteRune(cell.Rune)
+		}
+		if err := bw.WriteByte('\n'); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
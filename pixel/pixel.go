@@ -1,8 +1,11 @@
 package pixel
 
 import (
+	"fmt"
 	"image/color"
 	"math"
+	"strconv"
+	"strings"
 )
 
 func GetLuminance(pixelColor color.Color) float64 {
@@ -91,3 +94,100 @@ func LerpColor(c1, c2 color.Color, weight float64) color.NRGBA {
 func lerp(a, b, t float64) float64 {
 	return a + t*(b-a)
 }
+
+// RGBToLab converts sRGB color to CIELAB with D65 white point
+func RGBToLab(pixelColor color.Color) (float64, float64, float64) {
+	r, g, b, _ := pixelColor.RGBA()
+	rl := srgbToLinear(float64(r) / 65535.0)
+	gl := srgbToLinear(float64(g) / 65535.0)
+	bl := srgbToLinear(float64(b) / 65535.0)
+
+	x := (0.4124564*rl + 0.3575761*gl + 0.1804375*bl) / 0.95047
+	y := 0.2126729*rl + 0.7151522*gl + 0.0721750*bl
+	z := (0.0193339*rl + 0.1191920*gl + 0.9503041*bl) / 1.08883
+
+	fx, fy, fz := labF(x), labF(y), labF(z)
+	return 116*fy - 16, 500 * (fx - fy), 200 * (fy - fz)
+}
+
+func srgbToLinear(v float64) float64 {
+	if v <= 0.04045 {
+		return v / 12.92
+	}
+	return math.Pow((v+0.055)/1.055, 2.4)
+}
+
+func labF(t float64) float64 {
+	const delta = 6.0 / 29.0
+	if t > delta*delta*delta {
+		return math.Cbrt(t)
+	}
+	return t/(3*delta*delta) + 4.0/29.0
+}
+
+// ParseHexColor parses colors like #rrggbb or #rgb
+func ParseHexColor(s string) (color.NRGBA, error) {
+	s = strings.TrimPrefix(s, "#")
+	if len(s) == 3 {
+		s = string([]byte{s[0], s[0], s[1], s[1], s[2], s[2]})
+	}
+	if len(s) != 6 {
+		return color.NRGBA{}, fmt.Errorf("invalid color %q", s)
+	}
+	v, err := strconv.ParseUint(s, 16, 32)
+	if err != nil {
+		return color.NRGBA{}, fmt.Errorf("invalid color %q", s)
+	}
+	return color.NRGBA{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v), A: 255}, nil
+}
+
+type LuminanceModel int
+
+const (
+	// Rec601 is the luma of SD video, weighted sum of gamma encoded channels
+	Rec601 LuminanceModel = iota
+	// Rec709 is the relative luminance of sRGB in linear light
+	Rec709
+	// LabLightness is L* of CIELAB
+	LabLightness
+	// HSLLightness is (max+min)/2 of the channels
+	HSLLightness
+	// HSVValue is the max of the channels
+	HSVValue
+)
+
+func ParseLuminanceModel(s string) (LuminanceModel, error) {
+	switch s {
+	case "rec601":
+		return Rec601, nil
+	case "rec709":
+		return Rec709, nil
+	case "lab":
+		return LabLightness, nil
+	case "hsl":
+		return HSLLightness, nil
+	case "hsv":
+		return HSVValue, nil
+	}
+	return Rec601, fmt.Errorf("unknown luminance model %q", s)
+}
+
+// Luminance returns luminance of the color from 0 to 1
+func (m LuminanceModel) Luminance(pixelColor color.Color) float64 {
+	r, g, b, _ := pixelColor.RGBA()
+	rf := float64(r) / 65535.0
+	gf := float64(g) / 65535.0
+	bf := float64(b) / 65535.0
+	switch m {
+	case Rec709:
+		return 0.2126*srgbToLinear(rf) + 0.7152*srgbToLinear(gf) + 0.0722*srgbToLinear(bf)
+	case LabLightness:
+		l, _, _ := RGBToLab(pixelColor)
+		return math.Min(math.Max(l/100, 0), 1)
+	case HSLLightness:
+		return (math.Max(rf, math.Max(gf, bf)) + math.Min(rf, math.Min(gf, bf))) / 2
+	case HSVValue:
+		return math.Max(rf, math.Max(gf, bf))
+	}
+	return 0.299*rf + 0.587*gf + 0.114*bf
+}
@@ -0,0 +1,12 @@
+//go:build !(linux || darwin || freebsd || openbsd || netbsd || dragonfly)
+
+package utils
+
+import (
+	"errors"
+	"os"
+)
+
+func terminalSize(f *os.File) (int, int, error) {
+	return 0, 0, errors.New("terminal size is not supported on this system")
+}
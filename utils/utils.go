@@ -1,8 +1,10 @@
 package utils
 
 import (
+	"errors"
 	"image"
 	"os"
+	"strconv"
 
 	"github.com/disintegration/imaging"
 )
@@ -19,3 +21,18 @@ func OpenFile(filename string) (image.Image, error) {
 	}
 	return im, nil
 }
+
+// TerminalSize returns columns and rows of the terminal of stdout,
+// COLUMNS and LINES variables are used when stdout isn't a terminal
+func TerminalSize() (int, int, error) {
+	cols, rows, err := terminalSize(os.Stdout)
+	if err == nil && cols > 0 && rows > 0 {
+		return cols, rows, nil
+	}
+	envCols, errCols := strconv.Atoi(os.Getenv("COLUMNS"))
+	envRows, errRows := strconv.Atoi(os.Getenv("LINES"))
+	if errCols == nil && errRows == nil && envCols > 0 && envRows > 0 {
+		return envCols, envRows, nil
+	}
+	return 0, 0, errors.New("couldn't get terminal size")
+}